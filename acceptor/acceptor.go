@@ -0,0 +1,19 @@
+package acceptor
+
+import "net"
+
+// Acceptor type interface, an acceptor listens for client connections on a
+// transport and hands them over as net.Conn so the gateway can wrap them in
+// sessions regardless of the transport
+type Acceptor interface {
+	// Listen binds the address, it returns the error when it can not
+	Listen() error
+	// Serve accepts connections on the bound address until Stop
+	Serve()
+	// ListenAndServe listens and serves, listen errors are only logged
+	ListenAndServe()
+	// Stop closes the listener, a stopped acceptor does not serve again
+	Stop()
+	GetAddr() string
+	GetConnChan() chan net.Conn
+}
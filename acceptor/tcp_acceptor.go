@@ -0,0 +1,135 @@
+package acceptor
+
+import (
+	"crypto/tls"
+	"net"
+	"sync"
+
+	"github.com/micro/go-micro/v2/logger"
+	"github.com/wolfplus2048/mcbeam-plus/constants"
+)
+
+// TCPAcceptor struct
+type TCPAcceptor struct {
+	addr     string
+	connChan chan net.Conn
+	listener net.Listener
+	die      chan struct{} // closed once stopped
+	stopOnce sync.Once
+	certFile string
+	keyFile  string
+}
+
+// NewTCPAcceptor creates a new instance of tcp acceptor, passing a cert
+// and a key file enables tls
+func NewTCPAcceptor(addr string, certs ...string) *TCPAcceptor {
+	keyFile := ""
+	certFile := ""
+	if len(certs) != 2 && len(certs) != 0 {
+		panic(constants.ErrInvalidCertificates)
+	} else if len(certs) == 2 {
+		certFile = certs[0]
+		keyFile = certs[1]
+	}
+
+	return &TCPAcceptor{
+		addr:     addr,
+		connChan: make(chan net.Conn),
+		die:      make(chan struct{}),
+		certFile: certFile,
+		keyFile:  keyFile,
+	}
+}
+
+// GetAddr returns the addr the acceptor will listen on
+func (a *TCPAcceptor) GetAddr() string {
+	if a.listener != nil {
+		return a.listener.Addr().String()
+	}
+	return ""
+}
+
+// GetConnChan gets a connection channel
+func (a *TCPAcceptor) GetConnChan() chan net.Conn {
+	return a.connChan
+}
+
+// Stop stops the acceptor, the connections accepted but not yet read from
+// the connection channel are closed
+func (a *TCPAcceptor) Stop() {
+	a.stopOnce.Do(func() {
+		close(a.die)
+		if a.listener != nil {
+			a.listener.Close()
+		}
+	})
+}
+
+func (a *TCPAcceptor) stopped() bool {
+	select {
+	case <-a.die:
+		return true
+	default:
+		return false
+	}
+}
+
+func (a *TCPAcceptor) hasTLSCertificates() bool {
+	return a.certFile != "" && a.keyFile != ""
+}
+
+// Listen binds the address, with tls when the acceptor has certificates
+func (a *TCPAcceptor) Listen() error {
+	if a.hasTLSCertificates() {
+		crt, err := tls.LoadX509KeyPair(a.certFile, a.keyFile)
+		if err != nil {
+			return err
+		}
+		tlsCfg := &tls.Config{Certificates: []tls.Certificate{crt}}
+		listener, err := tls.Listen("tcp", a.addr, tlsCfg)
+		if err != nil {
+			return err
+		}
+		a.listener = listener
+		return nil
+	}
+
+	listener, err := net.Listen("tcp", a.addr)
+	if err != nil {
+		return err
+	}
+	a.listener = listener
+	return nil
+}
+
+// ListenAndServe using tcp acceptor
+func (a *TCPAcceptor) ListenAndServe() {
+	if err := a.Listen(); err != nil {
+		logger.Errorf("Failed to listen: %s", err.Error())
+		return
+	}
+	a.Serve()
+}
+
+// Serve accepts the connections until the acceptor is stopped
+func (a *TCPAcceptor) Serve() {
+	defer a.Stop()
+	logger.Infof("Acceptor [tcp] Listening on %s", a.listener.Addr().String())
+	for {
+		conn, err := a.listener.Accept()
+		if err != nil {
+			if a.stopped() {
+				return
+			}
+			logger.Errorf("Failed to accept TCP connection: %s", err.Error())
+			continue
+		}
+
+		select {
+		case a.connChan <- conn:
+		case <-a.die:
+			conn.Close()
+			return
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package acceptor
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTCPAcceptorAcceptAndStop(t *testing.T) {
+	a := NewTCPAcceptor("127.0.0.1:0")
+	assert.NoError(t, a.Listen())
+	served := make(chan struct{})
+	go func() {
+		a.Serve()
+		close(served)
+	}()
+
+	c, err := net.Dial("tcp", a.GetAddr())
+	assert.NoError(t, err)
+	defer c.Close()
+
+	select {
+	case conn := <-a.GetConnChan():
+		assert.Equal(t, c.LocalAddr().String(), conn.RemoteAddr().String())
+		conn.Close()
+	case <-time.After(time.Second):
+		t.Fatal("connection not accepted")
+	}
+
+	a.Stop()
+	select {
+	case <-served:
+	case <-time.After(time.Second):
+		t.Fatal("acceptor still serving after stop")
+	}
+	_, err = net.Dial("tcp", a.GetAddr())
+	assert.Error(t, err)
+}
+
+func TestTCPAcceptorListenError(t *testing.T) {
+	a := NewTCPAcceptor("127.0.0.1:0")
+	assert.NoError(t, a.Listen())
+	defer a.Stop()
+
+	b := NewTCPAcceptor(a.GetAddr())
+	assert.Error(t, b.Listen())
+}
+
+func TestTCPAcceptorStopWithPendingConn(t *testing.T) {
+	a := NewTCPAcceptor("127.0.0.1:0")
+	assert.NoError(t, a.Listen())
+	served := make(chan struct{})
+	go func() {
+		a.Serve()
+		close(served)
+	}()
+
+	// nobody reads the connection channel
+	c, err := net.Dial("tcp", a.GetAddr())
+	assert.NoError(t, err)
+	defer c.Close()
+	time.Sleep(10 * time.Millisecond)
+
+	a.Stop()
+	select {
+	case <-served:
+	case <-time.After(time.Second):
+		t.Fatal("acceptor blocked on the pending connection")
+	}
+	// the pending connection was closed
+	c.SetReadDeadline(time.Now().Add(time.Second))
+	_, err = c.Read(make([]byte, 1))
+	assert.Error(t, err)
+}
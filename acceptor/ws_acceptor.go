@@ -0,0 +1,179 @@
+package acceptor
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/websocket"
+	"github.com/micro/go-micro/v2/logger"
+	"github.com/wolfplus2048/mcbeam-plus/constants"
+)
+
+// WSAcceptor struct
+type WSAcceptor struct {
+	addr     string
+	connChan chan net.Conn
+	listener net.Listener
+	die      chan struct{} // closed once stopped
+	stopOnce sync.Once
+	certFile string
+	keyFile  string
+	origins  []string // origins allowed to connect, any when empty
+}
+
+// NewWSAcceptor returns a new instance of WSAcceptor, passing a cert and
+// a key file enables tls. Pages of any origin can connect unless
+// AllowOrigins restricts them.
+func NewWSAcceptor(addr string, certs ...string) *WSAcceptor {
+	keyFile := ""
+	certFile := ""
+	if len(certs) != 2 && len(certs) != 0 {
+		panic(constants.ErrInvalidCertificates)
+	} else if len(certs) == 2 {
+		certFile = certs[0]
+		keyFile = certs[1]
+	}
+
+	w := &WSAcceptor{
+		addr:     addr,
+		connChan: make(chan net.Conn),
+		die:      make(chan struct{}),
+		certFile: certFile,
+		keyFile:  keyFile,
+	}
+	return w
+}
+
+// AllowOrigins only upgrades the requests of browser pages served from one
+// of the origins, like "https://game.example.com". Requests without an
+// Origin header do not come from browsers and are always upgraded.
+func (w *WSAcceptor) AllowOrigins(origins ...string) *WSAcceptor {
+	w.origins = origins
+	return w
+}
+
+// checkOrigin tells whether the origin of the request is allowed
+func (w *WSAcceptor) checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if len(w.origins) == 0 || origin == "" {
+		return true
+	}
+	for _, o := range w.origins {
+		if strings.EqualFold(o, origin) {
+			return true
+		}
+	}
+	logger.Debugf("Upgrade refused, Origin=%s", origin)
+	return false
+}
+
+// GetAddr returns the addr the acceptor will listen on
+func (w *WSAcceptor) GetAddr() string {
+	if w.listener != nil {
+		return w.listener.Addr().String()
+	}
+	return ""
+}
+
+// GetConnChan gets a connection channel
+func (w *WSAcceptor) GetConnChan() chan net.Conn {
+	return w.connChan
+}
+
+type connHandler struct {
+	upgrader *websocket.Upgrader
+	connChan chan net.Conn
+	die      chan struct{}
+}
+
+func (h *connHandler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	conn, err := h.upgrader.Upgrade(rw, r, nil)
+	if err != nil {
+		logger.Errorf("Upgrade failure, URI=%s, Error=%s", r.RequestURI, err.Error())
+		return
+	}
+
+	c, err := NewWSConn(conn)
+	if err != nil {
+		logger.Errorf("Failed to create new ws connection: %s", err.Error())
+		return
+	}
+	select {
+	case h.connChan <- c:
+	case <-h.die:
+		c.Close()
+	}
+}
+
+func (w *WSAcceptor) hasTLSCertificates() bool {
+	return w.certFile != "" && w.keyFile != ""
+}
+
+// Listen binds the address, with tls when the acceptor has certificates
+func (w *WSAcceptor) Listen() error {
+	if w.hasTLSCertificates() {
+		crt, err := tls.LoadX509KeyPair(w.certFile, w.keyFile)
+		if err != nil {
+			return err
+		}
+		tlsCfg := &tls.Config{Certificates: []tls.Certificate{crt}}
+		listener, err := tls.Listen("tcp", w.addr, tlsCfg)
+		if err != nil {
+			return err
+		}
+		w.listener = listener
+		return nil
+	}
+
+	listener, err := net.Listen("tcp", w.addr)
+	if err != nil {
+		return err
+	}
+	w.listener = listener
+	return nil
+}
+
+// ListenAndServe listens and serve in the specified addr
+func (w *WSAcceptor) ListenAndServe() {
+	if err := w.Listen(); err != nil {
+		logger.Errorf("Failed to listen: %s", err.Error())
+		return
+	}
+	w.Serve()
+}
+
+// Serve upgrades the http connections to websocket until the acceptor is
+// stopped
+func (w *WSAcceptor) Serve() {
+	defer w.Stop()
+
+	var upgrader = websocket.Upgrader{
+		ReadBufferSize:  constants.IOBufferBytesSize,
+		WriteBufferSize: constants.IOBufferBytesSize,
+		CheckOrigin:     w.checkOrigin,
+	}
+
+	logger.Infof("Acceptor [ws] Listening on %s", w.listener.Addr().String())
+	http.Serve(w.listener, &connHandler{
+		upgrader: &upgrader,
+		connChan: w.connChan,
+		die:      w.die,
+	})
+}
+
+// Stop stops the acceptor, the connections upgraded but not yet read from
+// the connection channel are closed
+func (w *WSAcceptor) Stop() {
+	w.stopOnce.Do(func() {
+		close(w.die)
+		if w.listener == nil {
+			return
+		}
+		if err := w.listener.Close(); err != nil {
+			logger.Errorf("Failed to stop: %s", err.Error())
+		}
+	})
+}
@@ -0,0 +1,88 @@
+package acceptor
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWSAcceptorAcceptAndStop(t *testing.T) {
+	w := NewWSAcceptor("127.0.0.1:0")
+	assert.NoError(t, w.Listen())
+	served := make(chan struct{})
+	go func() {
+		w.Serve()
+		close(served)
+	}()
+
+	c, _, err := websocket.DefaultDialer.Dial("ws://"+w.GetAddr(), nil)
+	assert.NoError(t, err)
+	defer c.Close()
+	// the conn is handed over before the client sent anything, the gateway
+	// times it out if it stays silent
+	var conn net.Conn
+	select {
+	case conn = <-w.GetConnChan():
+	case <-time.After(time.Second):
+		t.Fatal("connection not accepted")
+	}
+	assert.NoError(t, c.WriteMessage(websocket.BinaryMessage, []byte("hel")))
+	assert.NoError(t, c.WriteMessage(websocket.BinaryMessage, []byte("lo")))
+	b := make([]byte, 5)
+	n, err := io.ReadFull(conn, b)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", string(b[:n]))
+	conn.Close()
+
+	w.Stop()
+	select {
+	case <-served:
+	case <-time.After(time.Second):
+		t.Fatal("acceptor still serving after stop")
+	}
+}
+
+func TestWSAcceptorOrigins(t *testing.T) {
+	w := NewWSAcceptor("127.0.0.1:0").AllowOrigins("https://game.example.com")
+	assert.NoError(t, w.Listen())
+	go w.Serve()
+	defer w.Stop()
+
+	tables := []struct {
+		name     string
+		origin   string
+		accepted bool
+	}{
+		{"allowed", "https://game.example.com", true},
+		{"other site", "https://evil.example.com", false},
+		{"no browser", "", true},
+	}
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			header := http.Header{}
+			if table.origin != "" {
+				header.Set("Origin", table.origin)
+			}
+			c, rsp, err := websocket.DefaultDialer.Dial("ws://"+w.GetAddr(), header)
+			if !table.accepted {
+				assert.Equal(t, websocket.ErrBadHandshake, err)
+				assert.Equal(t, http.StatusForbidden, rsp.StatusCode)
+				return
+			}
+			require.NoError(t, err)
+			defer c.Close()
+			select {
+			case conn := <-w.GetConnChan():
+				conn.Close()
+			case <-time.After(time.Second):
+				t.Fatal("connection not accepted")
+			}
+		})
+	}
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/micro/go-micro/v2/client"
 	"github.com/micro/go-micro/v2/client/selector"
 	"github.com/micro/go-micro/v2/logger"
+	"github.com/micro/go-micro/v2/metadata"
 	"github.com/wolfplus2048/mcbeam-plus/constants"
 	"github.com/wolfplus2048/mcbeam-plus/message"
 	"github.com/wolfplus2048/mcbeam-plus/protos"
@@ -34,6 +35,7 @@ import (
 	"github.com/wolfplus2048/mcbeam-plus/session"
 	"github.com/wolfplus2048/mcbeam-plus/util"
 	"net"
+	"strconv"
 	"strings"
 )
 
@@ -43,6 +45,8 @@ type Remote struct {
 	chDie        chan struct{}    // wait for close
 	frontendID   string           // the frontend that sent the request
 	frontendName string
+	addr         net.Addr             // address of the client, sent by the frontend
+	transport    string               // transport of the client, sent by the frontend
 	reply        string               // nats reply topic
 	rpcClient    client.Client        // rpc client
 	serializer   serialize.Serializer // message serializer
@@ -58,8 +62,10 @@ type pendingMessage struct {
 	err     bool         // if its an error message
 }
 
-// NewRemote create new Remote instance
+// NewRemote create new Remote instance, the session data is taken from
+// the cache or asked to the frontend when it is not sent along
 func NewRemote(
+	ctx context.Context,
 	sess *proto_mcbeam.Session,
 	reply string,
 	rpcClient client.Client,
@@ -75,20 +81,64 @@ func NewRemote(
 		serializer: serializer,
 	}
 
+	names := strings.Split(frontendID, "-")
+	if len(names) > 1 {
+		a.frontendName = names[0]
+	}
+
 	// binding session
 	s := session.New(a, false, sess.GetUid())
 	s.SetFrontendData(frontendID, sess.GetId())
-	err := s.SetDataEncoded(sess.GetData())
+	a.Session = s
+	a.setClient(ctx)
+	data, version := sess.GetData(), sess.GetVersion()
+	if len(data) == 0 && version > 0 {
+		var err error
+		if data, version, err = a.sessionData(ctx, version); err != nil {
+			return nil, err
+		}
+	}
+	err := s.SetDataEncoded(data)
 	if err != nil {
 		return nil, err
 	}
-	a.Session = s
-	names := strings.Split(frontendID, "-")
-	if len(names) > 1 {
-		a.frontendName = names[0]
-	}
+	s.SetVersion(version)
 	return a, nil
 }
+
+// setClient takes the client connection the frontend sends in the request
+// metadata
+func (a *Remote) setClient(ctx context.Context) {
+	if ip, ok := metadata.Get(ctx, "mcb-client-ip"); ok {
+		port, _ := metadata.Get(ctx, "mcb-client-port")
+		p, _ := strconv.Atoi(port)
+		a.addr = &net.TCPAddr{IP: net.ParseIP(ip), Port: p}
+	}
+	a.transport, _ = metadata.Get(ctx, "mcb-client-transport")
+	platform, _ := metadata.Get(ctx, "mcb-client-platform")
+	version, _ := metadata.Get(ctx, "mcb-client-version")
+	if platform != "" || version != "" {
+		a.Session.SetHandshakeData(&session.HandshakeData{
+			Sys: session.HandshakeClientData{Platform: platform, Version: version},
+		})
+	}
+}
+
+// sessionData returns the session data at version from the cache, or the
+// latest data of the frontend when the cached copy is stale
+func (a *Remote) sessionData(ctx context.Context, version uint64) ([]byte, uint64, error) {
+	key := sessionKey{frontendID: a.frontendID, id: a.Session.FrontendSessionID()}
+	if data, ok := sessionCache.get(key, version); ok {
+		return data, version, nil
+	}
+	rsp := &proto_mcbeam.Session{}
+	err := a.SendRequest(ctx, constants.GetSessionRoute, &proto_mcbeam.Session{Id: key.id}, rsp)
+	if err != nil {
+		return nil, 0, err
+	}
+	sessionCache.put(key, rsp.GetVersion(), rsp.GetData())
+	return rsp.GetData(), rsp.GetVersion(), nil
+}
 func (a *Remote) gateRoute(routeStr string) string {
 	return fmt.Sprintf("%s.%s.%s", a.frontendID, a.frontendName, routeStr)
 }
@@ -98,6 +148,7 @@ func (a *Remote) Kick(ctx context.Context) error {
 	if a.Session.UID() == "" {
 		return constants.ErrNoUIDBind
 	}
+	a.Flush()
 	rsp := &proto_mcbeam.KickAnswer{}
 	err := a.SendRequest(ctx, constants.KickRoute,
 		&proto_mcbeam.KickMsg{UserId: a.Session.UID()},
@@ -105,7 +156,10 @@ func (a *Remote) Kick(ctx context.Context) error {
 	return err
 }
 
-// Push pushes the message to the user
+// Push pushes the message to the user and returns the send error. When
+// push batching is on it is buffered with the other pushes to the
+// frontend and sent after the flush window, send errors are then only
+// logged.
 func (a *Remote) Push(route string, v interface{}) error {
 
 	switch d := v.(type) {
@@ -122,21 +176,61 @@ func (a *Remote) Push(route string, v interface{}) error {
 	)
 }
 
-// ResponseMID reponds the message with mid to the user
+// PushContext pushes the message to the user at once, along with the
+// pushes buffered before it, and waits until it is sent or ctx is done
+func (a *Remote) PushContext(ctx context.Context, route string, v interface{}) error {
+	logger.Debugf("Type=PushContext, id=%d, UID=%s, Route=%s", a.Session.ID(), a.Session.UID(), route)
+	return a.sendPush(
+		pendingMessage{ctx: ctx, typ: message.Push, route: route, payload: v}, a.Session.UID(),
+	)
+}
+
+// ResponseMID reponds the message with mid to the user, through the
+// frontend that owns the session
 func (a *Remote) ResponseMID(ctx context.Context, mid uint, v interface{}, isError ...bool) error {
-	logger.Fatal(constants.ErrNotImplemented.Error())
-	return nil
+	if mid <= 0 {
+		return constants.ErrSessionOnNotify
+	}
+	err := false
+	if len(isError) > 0 {
+		err = isError[0]
+	}
+	return a.send(pendingMessage{ctx: ctx, typ: message.Response, mid: mid, payload: v, err: err})
+}
+
+// Flush sends the pushes buffered for the frontend of the remote
+func (a *Remote) Flush() {
+	pushBatches.flush(a.frontendID)
 }
 
 // Close closes the remote
 func (a *Remote) Close() error { return nil }
 
-// RemoteAddr returns the remote address of the user
-func (a *Remote) RemoteAddr() net.Addr { return nil }
+// RemoteAddr returns the remote address of the user, nil when the frontend
+// did not send it
+func (a *Remote) RemoteAddr() net.Addr { return a.addr }
+
+// Transport returns the transport the user is connected to the frontend
+// with
+func (a *Remote) Transport() string { return a.transport }
 
-func (a *Remote) send(m pendingMessage, to string) (err error) {
-	logger.Fatal(constants.ErrNotImplemented.Error())
-	return nil
+func (a *Remote) send(m pendingMessage) (err error) {
+	payload, err := util.SerializeOrRaw(a.serializer, m.payload)
+	if err != nil {
+		return err
+	}
+	res := &proto_mcbeam.ResponseMsg{
+		SessionId: a.Session.FrontendSessionID(),
+		Mid:       uint64(m.mid),
+		Data:      payload,
+		Error:     m.err,
+	}
+	ctx := m.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	a.Flush()
+	return a.SendRequest(ctx, constants.ResponseMIDRoute, res, &proto_mcbeam.Response{})
 }
 
 func (a *Remote) sendPush(m pendingMessage, userID string) (err error) {
@@ -146,12 +240,25 @@ func (a *Remote) sendPush(m pendingMessage, userID string) (err error) {
 	}
 	push := &proto_mcbeam.PushMsg{
 		Route: m.route,
-		Uid:   a.Session.UID(),
+		Uid:   userID,
 		Data:  payload,
 	}
+	ctx := m.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
 
-	err = a.SendRequest(context.Background(), constants.PushRoute, push, &proto_mcbeam.Response{})
-	return err
+	q, window, size := pushBatches.queue(a)
+	if q == nil {
+		return a.SendRequest(ctx, constants.PushRoute, push, &proto_mcbeam.Response{})
+	}
+	batch := q.add(push, window, size)
+	if m.ctx == nil {
+		return nil
+	}
+	// the batch can hold the pushes of other callers, ctx only bounds the wait
+	go q.flush(context.Background())
+	return batch.wait(ctx, userID)
 }
 
 // SendRequest sends a request to a mcb_server
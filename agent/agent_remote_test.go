@@ -0,0 +1,55 @@
+package agent
+
+import (
+	"context"
+	"testing"
+
+	"github.com/micro/go-micro/v2/client"
+	"github.com/micro/go-micro/v2/metadata"
+	"github.com/stretchr/testify/assert"
+	"github.com/wolfplus2048/mcbeam-plus/protos"
+	"github.com/wolfplus2048/mcbeam-plus/session"
+)
+
+func TestRemoteClientFromMetadata(t *testing.T) {
+	ctx := metadata.NewContext(context.Background(), metadata.Metadata{
+		"mcb-client-ip":        "2001:db8::1",
+		"mcb-client-port":      "4321",
+		"mcb-client-transport": session.TransportWS,
+		"mcb-client-platform":  "ios",
+		"mcb-client-version":   "1.2.0",
+	})
+	a, err := NewRemote(ctx, &proto_mcbeam.Session{Id: 1, Uid: "u1"}, "", nil, "gate-1", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "[2001:db8::1]:4321", a.RemoteAddr().String())
+	assert.Equal(t, "2001:db8::1", a.Session.GetRemoteAddr())
+	assert.Equal(t, session.TransportWS, a.Session.Transport())
+	assert.Equal(t, "ios", a.Session.GetHandshakeData().Sys.Platform)
+	assert.Equal(t, "1.2.0", a.Session.GetHandshakeData().Sys.Version)
+
+	// frontends that do not send the client leave it unknown
+	a, err = NewRemote(context.Background(), &proto_mcbeam.Session{Id: 1, Uid: "u1"}, "", nil, "gate-1", nil)
+	assert.NoError(t, err)
+	assert.Nil(t, a.RemoteAddr())
+	assert.Equal(t, "", a.Session.GetRemoteAddr())
+	assert.Nil(t, a.Session.GetHandshakeData())
+}
+
+// ctxClient records the context of the calls
+type ctxClient struct {
+	batchClient
+	ctx context.Context
+}
+
+func (c *ctxClient) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	c.ctx = ctx
+	return nil
+}
+
+func TestRemoteResponseMIDWithoutContext(t *testing.T) {
+	c := &ctxClient{}
+	a, err := NewRemote(context.Background(), &proto_mcbeam.Session{Id: 1, Uid: "u1"}, "", c, "gate-1", nil)
+	assert.NoError(t, err)
+	assert.NoError(t, a.ResponseMID(nil, 1, []byte("ok")))
+	assert.NotNil(t, c.ctx)
+}
@@ -0,0 +1,169 @@
+package agent
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/micro/go-micro/v2/client"
+	"github.com/micro/go-micro/v2/client/selector"
+	"github.com/micro/go-micro/v2/logger"
+	"github.com/wolfplus2048/mcbeam-plus/constants"
+	"github.com/wolfplus2048/mcbeam-plus/protos"
+	"github.com/wolfplus2048/mcbeam-plus/util"
+)
+
+const (
+	// DefaultPushFlushWindow is a window fit for buffering the pushes to a
+	// frontend before sending them in one rpc, pushes are not buffered
+	// until SetPushBatching sets a window
+	DefaultPushFlushWindow = 5 * time.Millisecond
+	// DefaultPushBatchSize is the number of buffered pushes sent without
+	// waiting for the end of the flush window
+	DefaultPushBatchSize = 64
+)
+
+var pushBatches = newPushBatcher(0, DefaultPushBatchSize)
+
+// SetPushBatching sets how long the pushes to a frontend are buffered and
+// how many of them at most, a zero window sends each push on its own and
+// returns its error
+func SetPushBatching(window time.Duration, size int) {
+	pushBatches.mutex.Lock()
+	defer pushBatches.mutex.Unlock()
+	pushBatches.window = window
+	pushBatches.size = size
+}
+
+// pushBatcher buffers the pushes of the backend in one queue per frontend
+type pushBatcher struct {
+	mutex  sync.Mutex
+	window time.Duration
+	size   int
+	queues map[string]*pushQueue
+}
+
+type pushQueue struct {
+	frontendID   string
+	frontendName string
+	client       client.Client
+	sending      sync.Mutex // batches are sent one at a time, in order
+	mutex        sync.Mutex
+	current      *pushBatch
+}
+
+type pushBatch struct {
+	pushes []*proto_mcbeam.PushMsg
+	timer  *time.Timer
+	done   chan struct{}
+	err    error
+	failed map[string]bool
+}
+
+func newPushBatcher(window time.Duration, size int) *pushBatcher {
+	return &pushBatcher{
+		window: window,
+		size:   size,
+		queues: make(map[string]*pushQueue),
+	}
+}
+
+// queue returns the queue of the frontend of the remote, nil when pushes
+// are not buffered
+func (b *pushBatcher) queue(a *Remote) (*pushQueue, time.Duration, int) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.window <= 0 {
+		return nil, 0, 0
+	}
+	q, ok := b.queues[a.frontendID]
+	if !ok {
+		q = &pushQueue{
+			frontendID:   a.frontendID,
+			frontendName: a.frontendName,
+			client:       a.rpcClient,
+		}
+		b.queues[a.frontendID] = q
+	}
+	return q, b.window, b.size
+}
+
+// flush sends the pushes buffered for the frontend, so that a message
+// sent after them does not overtake them
+func (b *pushBatcher) flush(frontendID string) {
+	b.mutex.Lock()
+	q, ok := b.queues[frontendID]
+	b.mutex.Unlock()
+	if ok {
+		q.flush(context.Background())
+	}
+}
+
+// add buffers the push, the batch is sent once the window passed or it is
+// full
+func (q *pushQueue) add(push *proto_mcbeam.PushMsg, window time.Duration, size int) *pushBatch {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	if q.current == nil {
+		q.current = &pushBatch{done: make(chan struct{})}
+		q.current.timer = time.AfterFunc(window, func() {
+			q.flush(context.Background())
+		})
+	}
+	batch := q.current
+	batch.pushes = append(batch.pushes, push)
+	if size > 0 && len(batch.pushes) >= size {
+		batch.timer.Stop()
+		go q.flush(context.Background())
+	}
+	return batch
+}
+
+// flush sends the buffered pushes
+func (q *pushQueue) flush(ctx context.Context) {
+	q.sending.Lock()
+	defer q.sending.Unlock()
+	q.mutex.Lock()
+	batch := q.current
+	q.current = nil
+	q.mutex.Unlock()
+	if batch == nil {
+		return
+	}
+	batch.timer.Stop()
+
+	logger.Debugf("Type=PushBatch, Frontend=%s, Pushes=%d", q.frontendID, len(batch.pushes))
+	rsp := &proto_mcbeam.UsersAnswer{}
+	so := selector.WithStrategy(util.Select(q.frontendID))
+	req := q.client.NewRequest(q.frontendName, constants.PushBatchRoute,
+		&proto_mcbeam.PushBatchMsg{Pushes: batch.pushes})
+	batch.err = q.client.Call(ctx, req, rsp, client.WithSelectOption(so))
+	if batch.err != nil {
+		logger.Errorf("Failed to push %d messages to frontend %s: %s",
+			len(batch.pushes), q.frontendID, batch.err.Error())
+	} else if len(rsp.GetFailedUids()) > 0 {
+		logger.Debugf("Failed to push to uids %v on frontend %s", rsp.GetFailedUids(), q.frontendID)
+		batch.failed = make(map[string]bool, len(rsp.GetFailedUids()))
+		for _, uid := range rsp.GetFailedUids() {
+			batch.failed[uid] = true
+		}
+	}
+	close(batch.done)
+}
+
+// wait returns once the batch holding the push of uid was sent or ctx is
+// done, with the error of the push
+func (b *pushBatch) wait(ctx context.Context, uid string) error {
+	select {
+	case <-b.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	if b.err != nil {
+		return b.err
+	}
+	if b.failed[uid] {
+		return constants.ErrSessionNotFound
+	}
+	return nil
+}
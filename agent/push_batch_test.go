@@ -0,0 +1,123 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v2/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/wolfplus2048/mcbeam-plus/constants"
+	"github.com/wolfplus2048/mcbeam-plus/protos"
+)
+
+type batchRequest struct {
+	client.Request
+	body interface{}
+}
+
+func (r *batchRequest) Body() interface{} { return r.body }
+
+// batchClient records the routes of the batches it is asked to send
+type batchClient struct {
+	client.Client
+	mutex   sync.Mutex
+	batches [][]string
+	failed  []string
+}
+
+func (c *batchClient) NewRequest(service, endpoint string, req interface{}, opts ...client.RequestOption) client.Request {
+	return &batchRequest{body: req}
+}
+
+func (c *batchClient) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	var routes []string
+	for _, push := range req.Body().(*proto_mcbeam.PushBatchMsg).GetPushes() {
+		routes = append(routes, push.GetRoute())
+	}
+	c.batches = append(c.batches, routes)
+	rsp.(*proto_mcbeam.UsersAnswer).FailedUids = c.failed
+	return nil
+}
+
+func (c *batchClient) sent() [][]string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.batches
+}
+
+func TestPushBatchKeepsOrder(t *testing.T) {
+	c := &batchClient{}
+	b := newPushBatcher(time.Hour, 64)
+	q, window, size := b.queue(&Remote{frontendID: "gate-1", frontendName: "gate", rpcClient: c})
+
+	var routes []string
+	for i := 0; i < 20; i++ {
+		route := fmt.Sprintf("room.tick%d", i)
+		routes = append(routes, route)
+		q.add(&proto_mcbeam.PushMsg{Route: route, Uid: "u1"}, window, size)
+	}
+	b.flush("gate-1")
+	assert.Equal(t, [][]string{routes}, c.sent())
+
+	// nothing is left to send
+	b.flush("gate-1")
+	assert.Len(t, c.sent(), 1)
+}
+
+func TestPushBatchFlushes(t *testing.T) {
+	c := &batchClient{}
+	b := newPushBatcher(10*time.Millisecond, 2)
+	q, window, size := b.queue(&Remote{frontendID: "gate-1", frontendName: "gate", rpcClient: c})
+
+	q.add(&proto_mcbeam.PushMsg{Route: "a"}, window, size)
+	q.add(&proto_mcbeam.PushMsg{Route: "b"}, window, size)
+	assert.Eventually(t, func() bool { return len(c.sent()) == 1 }, time.Second, time.Millisecond)
+
+	q.add(&proto_mcbeam.PushMsg{Route: "c"}, window, size)
+	assert.Eventually(t, func() bool { return len(c.sent()) == 2 }, time.Second, time.Millisecond)
+	assert.Equal(t, [][]string{{"a", "b"}, {"c"}}, c.sent())
+}
+
+func TestPushBatchWait(t *testing.T) {
+	c := &batchClient{failed: []string{"u2"}}
+	b := newPushBatcher(time.Hour, 64)
+	q, window, size := b.queue(&Remote{frontendID: "gate-1", frontendName: "gate", rpcClient: c})
+
+	batch := q.add(&proto_mcbeam.PushMsg{Route: "a", Uid: "u1"}, window, size)
+	q.add(&proto_mcbeam.PushMsg{Route: "b", Uid: "u2"}, window, size)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, batch.wait(ctx, "u1"))
+
+	q.flush(context.Background())
+	assert.NoError(t, batch.wait(context.Background(), "u1"))
+	assert.Equal(t, constants.ErrSessionNotFound, batch.wait(context.Background(), "u2"))
+}
+
+// failingClient fails the calls it is asked to send
+type failingClient struct {
+	batchClient
+	err error
+}
+
+func (c *failingClient) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	return c.err
+}
+
+func TestPushWithoutBatching(t *testing.T) {
+	c := &failingClient{err: errors.New("frontend unreachable")}
+	a, err := NewRemote(context.Background(), &proto_mcbeam.Session{Id: 1, Uid: "u1"}, "", c, "gate-1", nil)
+	assert.NoError(t, err)
+
+	// pushes are not buffered by default, their errors are returned
+	q, _, _ := pushBatches.queue(a)
+	assert.Nil(t, q)
+	assert.Equal(t, c.err, a.Push("room.tick", []byte("1")))
+}
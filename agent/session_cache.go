@@ -0,0 +1,103 @@
+package agent
+
+import (
+	"container/list"
+	"context"
+	"sync"
+
+	"github.com/wolfplus2048/mcbeam-plus/protos"
+)
+
+// DefaultSessionCacheSize is the number of sessions whose data a backend
+// keeps, the least recently used ones are dropped first
+const DefaultSessionCacheSize = 10000
+
+var sessionCache = newSessionCache(DefaultSessionCacheSize)
+
+type sessionKey struct {
+	frontendID string
+	id         int64
+}
+
+type sessionEntry struct {
+	key     sessionKey
+	version uint64
+	data    []byte
+}
+
+// dataCache keeps the encoded data of the sessions backends got requests
+// from, frontends only send the version of the data along with requests
+type dataCache struct {
+	mutex   sync.Mutex
+	size    int
+	lru     *list.List
+	entries map[sessionKey]*list.Element
+}
+
+func newSessionCache(size int) *dataCache {
+	return &dataCache{
+		size:    size,
+		lru:     list.New(),
+		entries: make(map[sessionKey]*list.Element),
+	}
+}
+
+// get returns the data of the session when the cached copy is at version
+func (c *dataCache) get(key sessionKey, version uint64) ([]byte, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := el.Value.(*sessionEntry)
+	if entry.version != version {
+		return nil, false
+	}
+	c.lru.MoveToFront(el)
+	return entry.data, true
+}
+
+// put caches the data of the session, unless a newer version is cached
+func (c *dataCache) put(key sessionKey, version uint64, data []byte) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if el, ok := c.entries[key]; ok {
+		entry := el.Value.(*sessionEntry)
+		if entry.version <= version {
+			entry.version = version
+			entry.data = data
+		}
+		c.lru.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.lru.PushFront(&sessionEntry{key: key, version: version, data: data})
+	for c.lru.Len() > c.size {
+		el := c.lru.Back()
+		c.lru.Remove(el)
+		delete(c.entries, el.Value.(*sessionEntry).key)
+	}
+}
+
+// invalidate drops the session data older than version, all of it when
+// the session closed
+func (c *dataCache) invalidate(key sessionKey, version uint64, closed bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return
+	}
+	if closed || el.Value.(*sessionEntry).version < version {
+		c.lru.Remove(el)
+		delete(c.entries, key)
+	}
+}
+
+// HandleSessionInvalidate drops the cached session data the frontend
+// reports as stale, it subscribes to the events frontends publish
+func HandleSessionInvalidate(ctx context.Context, msg *proto_mcbeam.SessionInvalidate) error {
+	key := sessionKey{frontendID: msg.GetFrontendID(), id: msg.GetId()}
+	sessionCache.invalidate(key, msg.GetVersion(), msg.GetClosed())
+	return nil
+}
@@ -0,0 +1,72 @@
+package agent
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wolfplus2048/mcbeam-plus/protos"
+)
+
+func TestSessionCacheVersions(t *testing.T) {
+	c := newSessionCache(10)
+	key := sessionKey{frontendID: "gate-1", id: 1}
+
+	_, ok := c.get(key, 1)
+	assert.False(t, ok)
+
+	c.put(key, 2, []byte("v2"))
+	data, ok := c.get(key, 2)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("v2"), data)
+	_, ok = c.get(key, 3)
+	assert.False(t, ok)
+
+	// an older copy fetched late does not replace the newer one
+	c.put(key, 1, []byte("v1"))
+	data, ok = c.get(key, 2)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("v2"), data)
+}
+
+func TestSessionCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	c := newSessionCache(2)
+	k1 := sessionKey{frontendID: "gate-1", id: 1}
+	k2 := sessionKey{frontendID: "gate-1", id: 2}
+	k3 := sessionKey{frontendID: "gate-2", id: 1}
+
+	c.put(k1, 1, nil)
+	c.put(k2, 1, nil)
+	_, ok := c.get(k1, 1)
+	assert.True(t, ok)
+	c.put(k3, 1, nil)
+
+	_, ok = c.get(k2, 1)
+	assert.False(t, ok)
+	_, ok = c.get(k1, 1)
+	assert.True(t, ok)
+	_, ok = c.get(k3, 1)
+	assert.True(t, ok)
+}
+
+func TestHandleSessionInvalidate(t *testing.T) {
+	key := sessionKey{frontendID: "gate-1", id: 1}
+	sessionCache.put(key, 2, []byte("v2"))
+	defer sessionCache.invalidate(key, 0, true)
+
+	msg := &proto_mcbeam.SessionInvalidate{FrontendID: key.frontendID, Id: key.id, Version: 2}
+	assert.NoError(t, HandleSessionInvalidate(context.Background(), msg))
+	_, ok := sessionCache.get(key, 2)
+	assert.True(t, ok)
+
+	msg.Version = 3
+	assert.NoError(t, HandleSessionInvalidate(context.Background(), msg))
+	_, ok = sessionCache.get(key, 2)
+	assert.False(t, ok)
+
+	sessionCache.put(key, 3, []byte("v3"))
+	msg.Closed = true
+	assert.NoError(t, HandleSessionInvalidate(context.Background(), msg))
+	_, ok = sessionCache.get(key, 3)
+	assert.False(t, ok)
+}
@@ -37,16 +37,43 @@ const (
 	PushSessionRoute = "McbGate.PushSession"
 
 	PushRoute = "McbGate.Push"
+
+	// PushBatchRoute is the route used for sending the pushes a backend
+	// buffered for a frontend
+	PushBatchRoute = "McbGate.PushBatch"
 	// SessionBindRoute is the route used for binding session
 	BindRoute = "McbGate.Bind"
 
+	// PushSessionDeltaRoute is the route used for sending the session data
+	// changed on a backend
+	PushSessionDeltaRoute = "McbGate.PushSessionDelta"
+
+	// GetSessionRoute is the route used for getting the session data
+	GetSessionRoute = "McbGate.GetSession"
+
 	// KickRoute is the route used for kicking an user
 	KickRoute = "McbGate.Kick"
+
+	// ResponseMIDRoute is the route used for responding a client request
+	// after its handler returned
+	ResponseMIDRoute = "McbGate.ResponseMID"
+
+	// SessionCloseTopic is the broker topic frontends publish the close of
+	// bound sessions on
+	SessionCloseTopic = "mcbeam.session.close"
+
+	// SessionInvalidateTopic is the broker topic frontends publish the
+	// changes of session data on, for backends to drop their cached copy
+	SessionInvalidateTopic = "mcbeam.session.invalidate"
 )
 
 // SessionCtxKey is the context key where the session will be set
 var SessionCtxKey = "session"
 
+// MessageIDCtxKey is the context key where the id of the client request
+// being handled will be set
+var MessageIDCtxKey = "message-id"
+
 // LoggerCtxKey is the context key where the default logger will be set
 var LoggerCtxKey = "default-logger"
 
@@ -94,6 +121,14 @@ var GRPCExternalPortKey = "grpc-external-port"
 // RegionKey is the key to save the region tcp is on
 var RegionKey = "region"
 
+// RoutesMetadataKey is the registry node metadata key where servers
+// publish the json list of their client routes
+var RoutesMetadataKey = "mcb-routes"
+
+// SchemaMetadataKey is the registry node metadata key where servers
+// publish the json protobuf schema of their client routes
+var SchemaMetadataKey = "mcb-schema"
+
 // IP constants
 const (
 	IPVersionKey = "ipversion"
@@ -24,6 +24,7 @@ import "errors"
 
 // Errors that can occur during message handling.
 var (
+	ErrAdminNotLoopback               = errors.New("admin http endpoint without a token must listen on loopback")
 	ErrBindingNotFound                = errors.New("binding for this user was not found in etcd")
 	ErrBrokenPipe                     = errors.New("broken low-level pipe")
 	ErrBufferExceed                   = errors.New("session send buffer exceed")
@@ -41,6 +42,7 @@ var (
 	ErrGroupNotFound                  = errors.New("group not found")
 	ErrIllegalUID                     = errors.New("illegal uid")
 	ErrInvalidCertificates            = errors.New("certificates must be exactly two")
+	ErrInvalidResumeToken             = errors.New("invalid session resume token")
 	ErrInvalidSpanCarrier             = errors.New("tracing: invalid span carrier")
 	ErrKickingUsers                   = errors.New("failed to kick users, check array with failed uids")
 	ErrMemberAlreadyExists            = errors.New("member already exists in group")
@@ -54,6 +56,7 @@ var (
 	ErrNoBindingStorageModule         = errors.New("for sending remote pushes or using unique session module while using grpc you need to pass it a BindingStorage")
 	ErrNoConnectionToServer           = errors.New("rpc client has no connection to the chosen tcp")
 	ErrNoContextFound                 = errors.New("no context found")
+	ErrNoGroupStore                   = errors.New("distributed groups need a store")
 	ErrNoNatsConnectionString         = errors.New("you have to provide a nats url")
 	ErrNoServerTypeChosenForRPC       = errors.New("no tcp type chosen for sending RPC, send a full route in the format tcp.mcb.component")
 	ErrNoServerWithID                 = errors.New("can't find any tcp with the provided id")
@@ -62,6 +65,7 @@ var (
 	ErrNonsenseRPC                    = errors.New("you are making a rpc that may be processed locally, either specify a different tcp type or specify a tcp id")
 	ErrNotImplemented                 = errors.New("method not implemented")
 	ErrNotifyOnRequest                = errors.New("tried to notify a request route")
+	ErrObjectNotFound                 = errors.New("no object is associated with the session key")
 	ErrOnCloseBackend                 = errors.New("onclose callbacks are not allowed on backend servers")
 	ErrProtodescriptor                = errors.New("failed to get protobuf message descriptor")
 	ErrPushingToUsers                 = errors.New("failed to push message to users, check array with failed uids")
@@ -71,6 +75,7 @@ var (
 	ErrRPCServerNotInitialized        = errors.New("RPC tcp is not running")
 	ErrReplyShouldBeNotNull           = errors.New("reply must not be null")
 	ErrReplyShouldBePtr               = errors.New("reply must be a pointer")
+	ErrResponseDeferred               = errors.New("response will be sent later with Session.ResponseMID")
 	ErrRequestOnNotify                = errors.New("tried to request a notify route")
 	ErrRouterNotInitialized           = errors.New("router is not initialized")
 	ErrServerNotFound                 = errors.New("tcp not found")
@@ -79,7 +84,10 @@ var (
 	ErrSessionDuplication             = errors.New("session exists in the current group")
 	ErrSessionNotFound                = errors.New("session not found")
 	ErrSessionOnNotify                = errors.New("current session working on notify mode")
+	ErrStaleSessionData               = errors.New("session data changed on the frontend since it was read")
 	ErrTimeoutTerminatingBinaryModule = errors.New("timeout waiting to binary module to die")
+	ErrUIDAlreadyOnline               = errors.New("uid is already bound to another session")
+	ErrWrongObjectType                = errors.New("object type does not match the type registered for the session key")
 	ErrWrongValueType                 = errors.New("protobuf: convert on wrong type value")
 	ErrRateLimitExceeded              = errors.New("rate limit exceeded")
 	ErrReceivedMsgSmallerThanExpected = errors.New("received less data than expected, EOF?")
@@ -8,7 +8,9 @@ import (
 	"github.com/micro/go-micro/v2/registry/etcd"
 	"github.com/micro/go-micro/v2/web"
 	"github.com/wolfplus2048/mcbeam-plus"
+	"github.com/wolfplus2048/mcbeam-plus/acceptor"
 	proto_example "github.com/wolfplus2048/mcbeam-plus/example/proto"
+	"github.com/wolfplus2048/mcbeam-plus/gateway"
 	"github.com/wolfplus2048/mcbeam-plus/scheduler"
 	"net/http"
 	"time"
@@ -57,7 +59,9 @@ func main() {
 	go webService()
 	service := mcbeam.NewService(
 		mcbeam.Name("example"),
-		mcbeam.Registry(etcd.NewRegistry()))
+		mcbeam.Registry(etcd.NewRegistry()),
+		mcbeam.Gateway(gateway.NewGateway(
+			gateway.WithAcceptor(acceptor.NewWSAcceptor(mcbeam.DefaultClientAddress)))))
 	if err := service.Init(); err != nil {
 		logger.Fatal(err)
 	}
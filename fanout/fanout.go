@@ -0,0 +1,144 @@
+// Package fanout sends to users bound on any frontend of the cluster, with
+// one rpc for each frontend
+package fanout
+
+import (
+	"context"
+	"strings"
+
+	"github.com/micro/go-micro/v2/client"
+	"github.com/micro/go-micro/v2/client/selector"
+	"github.com/micro/go-micro/v2/logger"
+	"github.com/micro/go-micro/v2/registry"
+	"github.com/wolfplus2048/mcbeam-plus/constants"
+	"github.com/wolfplus2048/mcbeam-plus/protos"
+	"github.com/wolfplus2048/mcbeam-plus/util"
+)
+
+// Bindings finds the frontend a uid is bound on, modules.BindingStorage
+// implements it
+type Bindings interface {
+	GetUserFrontendID(uid string) (string, error)
+}
+
+// Send sends to the uids bound on the frontend, it returns the uids it
+// failed for
+type Send func(frontendID string, uids []string) ([]string, error)
+
+// ToUsers groups the uids by frontend and calls send once for each
+// frontend. A uid fails when it has no binding, or when it failed on every
+// frontend it was sent to. Without bindings the uids go to every frontend
+// of frontendType found in the registry.
+func ToUsers(b Bindings, r registry.Registry, frontendType string, uids []string, send Send) ([]string, error) {
+	groups, failed, err := ByFrontend(b, r, frontendType, uids)
+	if err != nil {
+		return uids, err
+	}
+
+	sent := make(map[string]int, len(uids))
+	fails := make(map[string]int, len(uids))
+	for frontendID, group := range groups {
+		for _, uid := range group {
+			sent[uid]++
+		}
+		res, err := send(frontendID, group)
+		if err != nil {
+			logger.Errorf("Failed to send to frontend %s: %s", frontendID, err.Error())
+			res = group
+		}
+		for _, uid := range res {
+			fails[uid]++
+		}
+	}
+	for _, uid := range uids {
+		if sent[uid] > 0 && fails[uid] == sent[uid] {
+			failed = append(failed, uid)
+		}
+	}
+	return failed, nil
+}
+
+// ByFrontend returns the uids grouped by the id of the frontend they are
+// bound on, and the uids with no binding. Without bindings all the uids go
+// to every frontend of frontendType.
+func ByFrontend(b Bindings, r registry.Registry, frontendType string, uids []string) (map[string][]string, []string, error) {
+	groups := make(map[string][]string)
+	if b == nil {
+		if frontendType == "" {
+			return nil, nil, constants.ErrFrontendTypeNotSpecified
+		}
+		if r == nil {
+			return nil, nil, constants.ErrNoServersAvailableOfType
+		}
+		services, err := r.GetService(frontendType)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, service := range services {
+			for _, node := range service.Nodes {
+				groups[node.Id] = uids
+			}
+		}
+		if len(groups) == 0 {
+			return nil, nil, constants.ErrNoServersAvailableOfType
+		}
+		return groups, nil, nil
+	}
+
+	var failed []string
+	for _, uid := range uids {
+		frontendID, err := b.GetUserFrontendID(uid)
+		if err != nil {
+			if err != constants.ErrBindingNotFound {
+				logger.Errorf("Failed to get frontend of uid %s: %s", uid, err.Error())
+			}
+			failed = append(failed, uid)
+			continue
+		}
+		groups[frontendID] = append(groups[frontendID], uid)
+	}
+	return groups, failed, nil
+}
+
+// PushToUsers returns the Send pushing the message to the users of a
+// frontend
+func PushToUsers(ctx context.Context, c client.Client, route string, data []byte) Send {
+	return func(frontendID string, uids []string) ([]string, error) {
+		push := &proto_mcbeam.PushToUsersMsg{
+			Route: route,
+			Uids:  uids,
+			Data:  data,
+		}
+		rsp, err := Gate(c, frontendID).PushToUsers(ctx, push, Select(frontendID))
+		return rsp.GetFailedUids(), err
+	}
+}
+
+// KickUsers returns the Send kicking the users of a frontend, the reason
+// is the close reason of their sessions
+func KickUsers(ctx context.Context, c client.Client, reason string) Send {
+	return func(frontendID string, uids []string) ([]string, error) {
+		kick := &proto_mcbeam.KickUsersMsg{
+			Uids:   uids,
+			Reason: reason,
+		}
+		rsp, err := Gate(c, frontendID).KickUsers(ctx, kick, Select(frontendID))
+		return rsp.GetFailedUids(), err
+	}
+}
+
+// Gate returns the McbGate client of the frontend service
+func Gate(c client.Client, frontendID string) proto_mcbeam.McbGateService {
+	return proto_mcbeam.NewMcbGateService(FrontendName(frontendID), c)
+}
+
+// Select is the call option sending the call to the frontend node
+func Select(frontendID string) client.CallOption {
+	return client.WithSelectOption(selector.WithStrategy(util.Select(frontendID)))
+}
+
+// FrontendName returns the service name of the frontend, frontend ids are
+// the service name and the node id joined by a dash
+func FrontendName(frontendID string) string {
+	return strings.SplitN(frontendID, "-", 2)[0]
+}
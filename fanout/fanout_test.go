@@ -0,0 +1,127 @@
+package fanout
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/micro/go-micro/v2/client"
+	"github.com/micro/go-micro/v2/registry"
+	"github.com/micro/go-micro/v2/registry/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wolfplus2048/mcbeam-plus/constants"
+	"github.com/wolfplus2048/mcbeam-plus/protos"
+)
+
+// bindings maps the uids to their frontend
+type bindings map[string]string
+
+func (b bindings) GetUserFrontendID(uid string) (string, error) {
+	if fid, ok := b[uid]; ok {
+		return fid, nil
+	}
+	return "", constants.ErrBindingNotFound
+}
+
+// frontends answers the sends as the frontends would, by frontend id
+type frontends struct {
+	mutex  sync.Mutex
+	failed map[string][]string
+	err    map[string]error
+	sent   map[string][]string
+}
+
+func (f *frontends) send(frontendID string, uids []string) ([]string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.sent == nil {
+		f.sent = make(map[string][]string)
+	}
+	f.sent[frontendID] = uids
+	return f.failed[frontendID], f.err[frontendID]
+}
+
+func sorted(uids []string) []string {
+	sort.Strings(uids)
+	return uids
+}
+
+func TestToUsers(t *testing.T) {
+	b := bindings{"u1": "gate-1", "u2": "gate-1", "u3": "gate-2", "u4": "gate-3", "u5": "gate-3"}
+	f := &frontends{
+		failed: map[string][]string{"gate-2": {"u3"}, "gate-3": {"u5"}},
+		err:    map[string]error{"gate-1": errors.New("unavailable")},
+	}
+
+	failed, err := ToUsers(b, nil, "", []string{"u1", "u2", "u3", "u4", "u5", "u6"}, f.send)
+	assert.NoError(t, err)
+	// u1 and u2 are on the frontend the rpc failed for, the failed uids of
+	// the other frontends are merged, u6 has no binding
+	assert.Equal(t, []string{"u1", "u2", "u3", "u5", "u6"}, sorted(failed))
+	assert.Equal(t, []string{"u1", "u2"}, sorted(f.sent["gate-1"]))
+	assert.Equal(t, []string{"u4", "u5"}, sorted(f.sent["gate-3"]))
+}
+
+func TestToUsersWithoutBindings(t *testing.T) {
+	r := memory.NewRegistry()
+	require.NoError(t, r.Register(&registry.Service{
+		Name:  "gate",
+		Nodes: []*registry.Node{{Id: "gate-1"}, {Id: "gate-2"}},
+	}))
+	f := &frontends{
+		// a uid is only bound on one of the frontends
+		failed: map[string][]string{"gate-1": {"u1", "u2"}, "gate-2": {"u2"}},
+	}
+
+	failed, err := ToUsers(nil, r, "gate", []string{"u1", "u2"}, f.send)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"u2"}, failed)
+	assert.Equal(t, []string{"u1", "u2"}, f.sent["gate-1"])
+	assert.Equal(t, []string{"u1", "u2"}, f.sent["gate-2"])
+
+	_, err = ToUsers(nil, r, "", []string{"u1"}, f.send)
+	assert.Equal(t, constants.ErrFrontendTypeNotSpecified, err)
+	failed, err = ToUsers(nil, r, "chat", []string{"u1"}, f.send)
+	assert.Error(t, err)
+	assert.Equal(t, []string{"u1"}, failed)
+}
+
+type gateRequest struct {
+	client.Request
+	service, endpoint string
+	body              interface{}
+}
+
+// gateClient answers the McbGate calls with the failed uids
+type gateClient struct {
+	client.Client
+	requests []*gateRequest
+	failed   []string
+}
+
+func (c *gateClient) NewRequest(service, endpoint string, req interface{}, opts ...client.RequestOption) client.Request {
+	return &gateRequest{service: service, endpoint: endpoint, body: req}
+}
+
+func (c *gateClient) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	c.requests = append(c.requests, req.(*gateRequest))
+	rsp.(*proto_mcbeam.UsersAnswer).FailedUids = c.failed
+	return nil
+}
+
+func TestKickUsers(t *testing.T) {
+	c := &gateClient{failed: []string{"u2"}}
+	failed, err := KickUsers(context.Background(), c, "banned")("gate-1", []string{"u1", "u2"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"u2"}, failed)
+
+	require.Len(t, c.requests, 1)
+	assert.Equal(t, "gate", c.requests[0].service)
+	assert.Equal(t, "McbGate.KickUsers", c.requests[0].endpoint)
+	kick := c.requests[0].body.(*proto_mcbeam.KickUsersMsg)
+	assert.Equal(t, "banned", kick.Reason)
+	assert.Equal(t, []string{"u1", "u2"}, kick.Uids)
+}
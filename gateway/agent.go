@@ -0,0 +1,382 @@
+package gateway
+
+import (
+	"context"
+	"net"
+	"sync"
+	"sync/atomic"
+
+	"github.com/micro/go-micro/v2/logger"
+	"github.com/wolfplus2048/mcbeam-plus/acceptor"
+	"github.com/wolfplus2048/mcbeam-plus/constants"
+	"github.com/wolfplus2048/mcbeam-plus/message"
+	"github.com/wolfplus2048/mcbeam-plus/packet"
+	"github.com/wolfplus2048/mcbeam-plus/serialize"
+	"github.com/wolfplus2048/mcbeam-plus/session"
+	"github.com/wolfplus2048/mcbeam-plus/util"
+)
+
+// agent corresponding to a client connection on the frontend, it implements
+// session.NetworkEntity
+type agent struct {
+	Session     *session.Session      // session
+	conn        net.Conn              // low-level conn fd, nil while waiting to be resumed
+	addr        net.Addr              // remote address of the last conn
+	transport   string                // transport of the last conn
+	pending     [][]byte              // packets written while detached, replayed on resume
+	pendingSize int                   // max number of pending packets
+	chSend      chan []byte           // packets waiting to be written by the writer goroutine
+	chCtrl      chan ctrlWrite        // handshake, heartbeat and kick packets, written after chSend
+	chRecv      chan *message.Message // client messages, dispatched one at a time in order
+	policy      BufferPolicy          // what to do when chSend is full
+	chDie       chan struct{}         // wait for close
+	closeMutex  sync.Mutex            // protect close
+	writeMutex  sync.Mutex            // serialize writes to conn, protect conn
+	state       int32                 // current agent state
+	resumeNonce int64                 // nonce of the last resume token issued
+	dropReason  atomic.Value          // why the conn was dropped by the server
+	limiter     *limiter              // rate limits of the client messages
+	encoder     atomic.Value          // *message.MessagesEncoder using the dictionary sent to the client
+	serializer  serialize.Serializer  // message serializer
+}
+
+// ctrlWrite is a packet the writer goroutine writes once the packets
+// queued before it are written
+type ctrlWrite struct {
+	p    []byte
+	done chan error // gets the write error, nil when nobody waits for it
+}
+
+// newAgent returns the agent of conn, it compresses the routes of the
+// dictionary of encoder once the client got it in the handshake response
+func newAgent(conn net.Conn, encoder *message.MessagesEncoder, opts Options) *agent {
+	a := &agent{
+		conn:        conn,
+		addr:        conn.RemoteAddr(),
+		transport:   transportOf(conn),
+		pendingSize: opts.ResumeBufferSize,
+		chSend:      make(chan []byte, opts.MessagesBufferSize),
+		chCtrl:      make(chan ctrlWrite, 1),
+		chRecv:      make(chan *message.Message, opts.MessagesBufferSize),
+		policy:      opts.BufferPolicy,
+		limiter:     newLimiter(opts),
+		chDie:       make(chan struct{}),
+		state:       constants.StatusStart,
+		serializer:  opts.Serializer,
+	}
+	a.setDictionary(encoder, nil)
+	a.Session = session.New(a, true)
+	go a.writeLoop()
+	return a
+}
+
+// Push pushes the message to the client
+func (a *agent) Push(route string, v interface{}) error {
+	if a.status() == constants.StatusClosed {
+		return constants.ErrBrokenPipe
+	}
+	switch d := v.(type) {
+	case []byte:
+		logger.Debugf("Type=Push, id=%d, UID=%s, Route=%s, Data=%dbytes",
+			a.Session.ID(), a.Session.UID(), route, len(d))
+	default:
+		logger.Debugf("Type=Push, id=%d, UID=%s, Route=%s, Data=%+v",
+			a.Session.ID(), a.Session.UID(), route, v)
+	}
+	return a.send(&message.Message{Type: message.Push, Route: route}, v)
+}
+
+// ResponseMID responds the message with mid to the client
+func (a *agent) ResponseMID(ctx context.Context, mid uint, v interface{}, isError ...bool) error {
+	if a.status() == constants.StatusClosed {
+		return constants.ErrBrokenPipe
+	}
+	if mid <= 0 {
+		return constants.ErrSessionOnNotify
+	}
+	m := &message.Message{Type: message.Response, ID: mid}
+	if len(isError) > 0 {
+		m.Err = isError[0]
+	}
+	return a.send(m, v)
+}
+
+// Kick notifies the client it is being disconnected, the close reason of
+// the session is sent along once the queued messages are written
+func (a *agent) Kick(ctx context.Context) error {
+	p, err := packet.EncodeKick(a.Session.CloseReason())
+	if err != nil {
+		return err
+	}
+	return a.writeAfterQueue(p)
+}
+
+// Close closes the agent, cleans inner state and closes the low-level connection.
+// Any blocked Read or Write operations will be unblocked and return errors.
+func (a *agent) Close() error {
+	a.closeMutex.Lock()
+	defer a.closeMutex.Unlock()
+	if a.status() == constants.StatusClosed {
+		return constants.ErrCloseClosedSession
+	}
+	a.setStatus(constants.StatusClosed)
+	logger.Debugf("Session closed, ID=%d, UID=%s, IP=%s",
+		a.Session.ID(), a.Session.UID(), a.RemoteAddr())
+
+	close(a.chDie)
+	a.Session.RunCloseCallbacks()
+	if conn := a.connection(); conn != nil {
+		return conn.Close()
+	}
+	return nil
+}
+
+// RemoteAddr returns the remote network address of the client
+func (a *agent) RemoteAddr() net.Addr {
+	a.writeMutex.Lock()
+	defer a.writeMutex.Unlock()
+	return a.addr
+}
+
+// Transport returns the transport the client is connected with
+func (a *agent) Transport() string {
+	a.writeMutex.Lock()
+	defer a.writeMutex.Unlock()
+	return a.transport
+}
+
+func transportOf(conn net.Conn) string {
+	if _, ok := conn.(*acceptor.WSConn); ok {
+		return session.TransportWS
+	}
+	return session.TransportTCP
+}
+
+// SendRequest is not supported on frontend sessions, they already live on
+// the server that owns them
+func (a *agent) SendRequest(ctx context.Context, route string, arg interface{}, reply interface{}) error {
+	return constants.ErrNotImplemented
+}
+
+func (a *agent) status() int32 {
+	return atomic.LoadInt32(&a.state)
+}
+
+func (a *agent) setStatus(state int32) {
+	atomic.StoreInt32(&a.state, state)
+}
+
+func (a *agent) send(m *message.Message, v interface{}) error {
+	payload, err := util.SerializeOrRaw(a.serializer, v)
+	if err != nil {
+		return err
+	}
+	m.Data = payload
+	em, err := a.encoder.Load().(*message.MessagesEncoder).Encode(m)
+	if err != nil {
+		return err
+	}
+	p, err := packet.Encode(packet.Data, em)
+	if err != nil {
+		return err
+	}
+	return a.enqueue(p)
+}
+
+// enqueue hands the packet to the writer goroutine so the caller does not
+// wait for slow clients, the buffer policy decides what happens when the
+// send buffer is full
+func (a *agent) enqueue(p []byte) error {
+	for {
+		select {
+		case a.chSend <- p:
+			sendQueueDepth.Inc()
+			return nil
+		default:
+		}
+
+		switch a.policy {
+		case BufferDropOldest:
+			select {
+			case <-a.chSend:
+				sendQueueDepth.Dec()
+				sendBufferOverflows.WithLabelValues(a.policy.String()).Inc()
+				logger.Warnf("Send buffer full, dropped oldest message, ID=%d, UID=%s", a.Session.ID(), a.Session.UID())
+			default:
+			}
+		case BufferKick:
+			sendBufferOverflows.WithLabelValues(a.policy.String()).Inc()
+			logger.Warnf("Send buffer full, kicking session, ID=%d, UID=%s", a.Session.ID(), a.Session.UID())
+			a.Session.SetCloseReason(session.CloseReasonBufferExceed)
+			go a.Session.Kick(context.Background())
+			return constants.ErrBufferExceed
+		default:
+			sendBufferOverflows.WithLabelValues(a.policy.String()).Inc()
+			logger.Warnf("Send buffer full, dropped message, ID=%d, UID=%s", a.Session.ID(), a.Session.UID())
+			return constants.ErrBufferExceed
+		}
+	}
+}
+
+// setDictionary makes the agent compress the routes of dict, the
+// dictionary the client got
+func (a *agent) setDictionary(encoder *message.MessagesEncoder, dict map[string]uint16) {
+	a.encoder.Store(encoder.WithDictionary(dict))
+}
+
+// receive queues a client message for dispatch, it blocks the reader of
+// the connection while the queue is full
+func (a *agent) receive(msg *message.Message) {
+	select {
+	case a.chRecv <- msg:
+	case <-a.chDie:
+	}
+}
+
+// writeLoop writes the queued packets until the agent is closed
+func (a *agent) writeLoop() {
+	defer func() {
+		sendQueueDepth.Sub(float64(len(a.chSend)))
+	}()
+	for {
+		select {
+		case p := <-a.chSend:
+			sendQueueDepth.Dec()
+			a.write(p)
+		case w := <-a.chCtrl:
+			a.flush()
+			err := a.write(w.p)
+			if w.done != nil {
+				w.done <- err
+			}
+		case <-a.chDie:
+			return
+		}
+	}
+}
+
+// flush writes the packets queued so far, the ones queued meanwhile are
+// left to the writer loop
+func (a *agent) flush() {
+	for n := len(a.chSend); n > 0; n-- {
+		select {
+		case p := <-a.chSend:
+			sendQueueDepth.Dec()
+			a.write(p)
+		default:
+			// dropped by BufferDropOldest
+			return
+		}
+	}
+}
+
+// writeAfterQueue writes the packet once the queued packets are written,
+// it returns once the packet is written
+func (a *agent) writeAfterQueue(p []byte) error {
+	w := ctrlWrite{p: p, done: make(chan error, 1)}
+	select {
+	case a.chCtrl <- w:
+	case <-a.chDie:
+		return constants.ErrBrokenPipe
+	}
+	select {
+	case err := <-w.done:
+		return err
+	case <-a.chDie:
+		return constants.ErrBrokenPipe
+	}
+}
+
+// heartbeat queues the heartbeat packet after the queued packets, unless
+// a packet of the kind is already waiting
+func (a *agent) heartbeat(p []byte) {
+	select {
+	case a.chCtrl <- ctrlWrite{p: p}:
+	default:
+	}
+}
+
+func (a *agent) write(b []byte) error {
+	a.writeMutex.Lock()
+	defer a.writeMutex.Unlock()
+	if a.conn == nil {
+		// keep the newest packets for the client to get them on resume
+		if len(a.pending) >= a.pendingSize && len(a.pending) > 0 {
+			a.pending = a.pending[1:]
+		}
+		if a.pendingSize > 0 {
+			a.pending = append(a.pending, b)
+		}
+		return nil
+	}
+	if _, err := a.conn.Write(b); err != nil {
+		logger.Errorf("Failed to write in conn: %s", err.Error())
+		return err
+	}
+	return nil
+}
+
+func (a *agent) connection() net.Conn {
+	a.writeMutex.Lock()
+	defer a.writeMutex.Unlock()
+	return a.conn
+}
+
+// owns reports whether conn is the connection the agent writes to
+func (a *agent) owns(conn net.Conn) bool {
+	return a.connection() == conn
+}
+
+// detach releases the connection, packets written until the next attach
+// are kept pending
+func (a *agent) detach() net.Conn {
+	a.writeMutex.Lock()
+	defer a.writeMutex.Unlock()
+	conn := a.conn
+	a.conn = nil
+	a.pending = make([][]byte, 0)
+	return conn
+}
+
+// attach makes the agent write to conn, the first packet and then the
+// pending ones are written before anything else, a previous connection
+// is closed
+func (a *agent) attach(conn net.Conn, first []byte) error {
+	a.writeMutex.Lock()
+	old := a.conn
+	a.conn = conn
+	a.addr = conn.RemoteAddr()
+	a.transport = transportOf(conn)
+	pending := a.pending
+	a.pending = nil
+	a.dropReason.Store("")
+	var err error
+	for _, b := range append([][]byte{first}, pending...) {
+		if _, err = conn.Write(b); err != nil {
+			break
+		}
+	}
+	a.writeMutex.Unlock()
+
+	if old != nil {
+		old.Close()
+	}
+	return err
+}
+
+// drop closes the connection without closing the session, the connection
+// handler then closes or parks the session
+func (a *agent) drop(reason string) {
+	a.dropReason.Store(reason)
+	if conn := a.connection(); conn != nil {
+		conn.Close()
+	}
+}
+
+// closeReason returns why the connection was lost
+func (a *agent) closeReason() string {
+	if reason, ok := a.dropReason.Load().(string); ok && reason != "" {
+		return reason
+	}
+	return session.CloseReasonDisconnect
+}
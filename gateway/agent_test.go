@@ -0,0 +1,121 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wolfplus2048/mcbeam-plus/constants"
+	"github.com/wolfplus2048/mcbeam-plus/message"
+	"github.com/wolfplus2048/mcbeam-plus/packet"
+	"github.com/wolfplus2048/mcbeam-plus/session"
+)
+
+// fullAgent returns an agent with its send buffer of two packets full, its
+// writer waits for the client to read the packet it is writing
+func fullAgent(t *testing.T, policy BufferPolicy) (*agent, *testClient) {
+	server, client := net.Pipe()
+	a := newAgent(server, message.NewMessagesEncoder(false), newOptions(MessagesBufferSize(2), WithBufferPolicy(policy)))
+	a.setStatus(constants.StatusWorking)
+	t.Cleanup(func() {
+		a.Close()
+		client.Close()
+	})
+
+	require.NoError(t, a.Push("room.tick0", []byte("0")))
+	require.Eventually(t, func() bool { return len(a.chSend) == 0 }, time.Second, time.Millisecond)
+	for i := 1; i < 3; i++ {
+		require.NoError(t, a.Push(fmt.Sprintf("room.tick%d", i), []byte("tick")))
+	}
+	return a, &testClient{t: t, conn: client, decoder: packet.NewPomeloDecoder()}
+}
+
+func (c *testClient) routes(n int) []string {
+	routes := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		routes = append(routes, c.push().Route)
+	}
+	return routes
+}
+
+func (c *testClient) kicked() string {
+	p, err := c.read(time.Second)
+	require.NoError(c.t, err)
+	require.Equal(c.t, packet.Type(packet.Kick), p.Type)
+	body := &packet.KickBody{}
+	require.NoError(c.t, json.Unmarshal(p.Data, body))
+	return body.Reason
+}
+
+func TestBufferPolicy(t *testing.T) {
+	tables := []struct {
+		policy BufferPolicy
+		err    error
+		routes []string
+		kicked bool
+	}{
+		{BufferDropNewest, constants.ErrBufferExceed, []string{"room.tick0", "room.tick1", "room.tick2"}, false},
+		{BufferDropOldest, nil, []string{"room.tick0", "room.tick2", "room.tick3"}, false},
+		{BufferKick, constants.ErrBufferExceed, []string{"room.tick0", "room.tick1", "room.tick2"}, true},
+	}
+	for _, table := range tables {
+		t.Run(table.policy.String(), func(t *testing.T) {
+			a, c := fullAgent(t, table.policy)
+			closed := onClose(t, a.Session)
+			assert.Equal(t, table.err, a.Push("room.tick3", []byte("tick")))
+
+			// a kick does not overtake the queued messages
+			assert.Equal(t, table.routes, c.routes(len(table.routes)))
+			if !table.kicked {
+				return
+			}
+			assert.Equal(t, session.CloseReasonBufferExceed, c.kicked())
+			select {
+			case reason := <-closed:
+				assert.Equal(t, session.CloseReasonBufferExceed, reason)
+			case <-time.After(time.Second):
+				t.Fatal("session not closed")
+			}
+		})
+	}
+}
+
+func TestKickAfterQueuedMessages(t *testing.T) {
+	a, c := fullAgent(t, BufferDropNewest)
+	kicked := make(chan error, 1)
+	go func() { kicked <- a.Session.Kick(context.Background()) }()
+
+	assert.Equal(t, []string{"room.tick0", "room.tick1", "room.tick2"}, c.routes(3))
+	assert.Equal(t, session.CloseReasonKick, c.kicked())
+	select {
+	case err := <-kicked:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("kick not returned")
+	}
+	_, err := c.read(100 * time.Millisecond)
+	assert.Error(t, err)
+}
+
+func TestDefaultBuffer(t *testing.T) {
+	opts := NewGateway(WithBufferPolicy(BufferDropNewest)).Options()
+	assert.Equal(t, DefaultMessagesBufferSize, opts.MessagesBufferSize)
+
+	// the client does not read, the writer holds the first packet and the
+	// others wait in the buffer
+	server, client := net.Pipe()
+	a := newAgent(server, message.NewMessagesEncoder(false), opts)
+	a.setStatus(constants.StatusWorking)
+	t.Cleanup(func() {
+		a.Close()
+		client.Close()
+	})
+	for i := 0; i < DefaultMessagesBufferSize; i++ {
+		assert.NoError(t, a.Push(fmt.Sprintf("room.tick%d", i), []byte("tick")))
+	}
+}
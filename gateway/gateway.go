@@ -0,0 +1,433 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/micro/go-micro/v2/client"
+	"github.com/micro/go-micro/v2/client/selector"
+	e "github.com/micro/go-micro/v2/errors"
+	"github.com/micro/go-micro/v2/logger"
+	"github.com/wolfplus2048/mcbeam-plus/acceptor"
+	"github.com/wolfplus2048/mcbeam-plus/constants"
+	"github.com/wolfplus2048/mcbeam-plus/message"
+	"github.com/wolfplus2048/mcbeam-plus/packet"
+	"github.com/wolfplus2048/mcbeam-plus/protos"
+	"github.com/wolfplus2048/mcbeam-plus/route"
+	"github.com/wolfplus2048/mcbeam-plus/session"
+	"github.com/wolfplus2048/mcbeam-plus/util"
+)
+
+// Gateway accepts client connections, keeps their frontend sessions and
+// forwards client messages to the backend servers
+type Gateway interface {
+	Init(opts ...Option) error
+	Options() Options
+	Start() error
+	Stop() error
+	String() string
+}
+
+type gateway struct {
+	sync.RWMutex
+	opts       Options
+	frontendID string
+	encoder    *message.MessagesEncoder
+	heartbeat  []byte // encoded heartbeat packet
+	acceptors  []acceptor.Acceptor
+	agents     sync.Map // session id -> *agent
+	die        chan struct{}
+	started    bool
+	hooks      []*session.HookHandle
+
+	clusterSchema atomic.Value // *schema.Schema of the cluster routes
+
+	resumeMutex sync.Mutex
+	resumeKey   []byte           // signs resume tokens
+	parked      map[int64]*agent // sessions waiting to be resumed by id
+}
+
+// NewGateway returns a new gateway
+func NewGateway(opt ...Option) Gateway {
+	return &gateway{
+		opts: newOptions(opt...),
+	}
+}
+
+func (g *gateway) Init(opts ...Option) error {
+	g.Lock()
+	defer g.Unlock()
+	for _, o := range opts {
+		o(&g.opts)
+	}
+	return nil
+}
+
+func (g *gateway) Options() Options {
+	g.RLock()
+	defer g.RUnlock()
+	return g.opts
+}
+
+func (g *gateway) Start() error {
+	g.Lock()
+	defer g.Unlock()
+	if g.started {
+		return nil
+	}
+	if g.opts.Client == nil {
+		return constants.ErrRPCClientNotInitialized
+	}
+	if g.opts.Server == nil {
+		return constants.ErrRPCServerNotInitialized
+	}
+	srvOpts := g.opts.Server.Options()
+	// same id the grpc server registers its node with
+	g.frontendID = srvOpts.Name + "-" + srvOpts.Id
+	g.encoder = message.NewMessagesEncoder(g.opts.DataCompression)
+	hb, err := packet.Encode(packet.Heartbeat, nil)
+	if err != nil {
+		return err
+	}
+	g.heartbeat = hb
+
+	if err = g.initResume(); err != nil {
+		return err
+	}
+
+	if g.opts.Registry == nil {
+		g.opts.Registry = g.opts.Client.Options().Registry
+	}
+	if err = g.loadRoutes(); err != nil {
+		// clients of the routes get them on the next refresh
+		logger.Errorf("Failed to load cluster routes: %s", err.Error())
+	}
+
+	acceptors := g.opts.Acceptors
+	if len(acceptors) == 0 {
+		acceptors = []acceptor.Acceptor{acceptor.NewTCPAcceptor(g.opts.Address)}
+	}
+	for i, acc := range acceptors {
+		if err = acc.Listen(); err != nil {
+			for _, started := range acceptors[:i] {
+				started.Stop()
+			}
+			return err
+		}
+	}
+	g.acceptors = acceptors
+
+	err = proto_mcbeam.RegisterMcbGateHandler(g.opts.Server, &gateHandler{name: srvOpts.Name, gate: g})
+	if err != nil {
+		g.stopAcceptors()
+		return err
+	}
+
+	g.hooks = []*session.HookHandle{
+		session.OnSessionClose(g.publishSessionClose),
+		session.OnSessionDataChange(g.publishSessionInvalidate),
+	}
+
+	g.die = make(chan struct{})
+	for _, acc := range g.acceptors {
+		go acc.Serve()
+		go g.serve(acc)
+	}
+	go g.refreshRoutes()
+	if g.opts.HeartbeatInterval > 0 {
+		go g.sweep()
+	}
+	g.started = true
+	return nil
+}
+
+func (g *gateway) Stop() error {
+	g.Lock()
+	defer g.Unlock()
+	if !g.started {
+		return nil
+	}
+	g.started = false
+	close(g.die)
+	g.stopAcceptors()
+	session.CloseAll()
+	for _, h := range g.hooks {
+		h.Remove()
+	}
+	return nil
+}
+
+func (g *gateway) stopAcceptors() {
+	for _, acc := range g.acceptors {
+		acc.Stop()
+	}
+}
+
+func (g *gateway) String() string {
+	return "gateway"
+}
+
+// publishSessionClose tells the backend servers a session closed, so they
+// drop its cached data and, when it was bound, clean up after its user
+func (g *gateway) publishSessionClose(s *session.Session) {
+	if s.Version() > 0 {
+		g.publish(constants.SessionInvalidateTopic, &proto_mcbeam.SessionInvalidate{
+			FrontendID: g.frontendID,
+			Id:         s.ID(),
+			Closed:     true,
+		})
+	}
+	if s.UID() != "" {
+		g.publish(constants.SessionCloseTopic, &proto_mcbeam.SessionClose{
+			Uid:        s.UID(),
+			FrontendID: g.frontendID,
+			Id:         s.ID(),
+		})
+	}
+}
+
+// publishSessionInvalidate tells the backend servers the session data they
+// cached is stale, they get it again on the next request of the session
+func (g *gateway) publishSessionInvalidate(s *session.Session) {
+	msg := &proto_mcbeam.SessionInvalidate{
+		FrontendID: g.frontendID,
+		Id:         s.ID(),
+		Version:    s.Version(),
+	}
+	// the version in each request keeps backends consistent, the event only
+	// frees their cache early so it is not waited for
+	go g.publish(constants.SessionInvalidateTopic, msg)
+}
+
+func (g *gateway) publish(topic string, msg interface{}) {
+	if err := g.opts.Client.Publish(context.Background(), g.opts.Client.NewMessage(topic, msg)); err != nil {
+		logger.Errorf("Failed to publish to %s: %s", topic, err.Error())
+	}
+}
+
+func (g *gateway) serve(acc acceptor.Acceptor) {
+	for {
+		select {
+		case conn := <-acc.GetConnChan():
+			go g.handle(conn)
+		case <-g.die:
+			return
+		}
+	}
+}
+
+// sweep sends heartbeats to the clients and closes the sessions that
+// stayed silent for more than MaxMissedHeartbeats intervals
+func (g *gateway) sweep() {
+	ticker := time.NewTicker(g.opts.HeartbeatInterval)
+	defer ticker.Stop()
+
+	timeout := g.opts.HeartbeatInterval * time.Duration(g.opts.MaxMissedHeartbeats)
+	for {
+		select {
+		case <-ticker.C:
+			deadline := time.Now().Add(-timeout).Unix()
+			g.agents.Range(func(_, v interface{}) bool {
+				a := v.(*agent)
+				if a.Session.LastTime() < deadline {
+					logger.Debugf("Session heartbeat timeout, ID=%d, UID=%s, LastTime=%d",
+						a.Session.ID(), a.Session.UID(), a.Session.LastTime())
+					a.drop(session.CloseReasonHeartbeatTimeout)
+					return true
+				}
+				if a.status() == constants.StatusWorking {
+					a.heartbeat(g.heartbeat)
+				}
+				return true
+			})
+		case <-g.die:
+			return
+		}
+	}
+}
+
+func (g *gateway) handle(conn net.Conn) {
+	a := newAgent(conn, g.encoder, g.opts)
+	go g.dispatch(a)
+	logger.Debugf("New session established, ID=%d, IP=%s", a.Session.ID(), conn.RemoteAddr())
+	g.agents.Store(a.Session.ID(), a)
+	defer func() {
+		// a is the agent serving conn, it changes when the client resumes a session
+		if g.release(a, conn) {
+			return
+		}
+		a.Session.SetCloseReason(a.closeReason())
+		a.Session.Close()
+	}()
+
+	decoder := packet.NewPomeloDecoder()
+	buf := make([]byte, constants.IOBufferBytesSize)
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			logger.Debugf("Session read error, ID=%d: %s", a.Session.ID(), err.Error())
+			return
+		}
+		packets, err := decoder.Decode(buf[:n])
+		if err != nil {
+			logger.Errorf("Failed to decode packets, ID=%d: %s", a.Session.ID(), err.Error())
+			return
+		}
+		if len(packets) > 0 {
+			a.Session.Touch()
+		}
+		for _, p := range packets {
+			if a, err = g.processPacket(a, p); err != nil {
+				logger.Errorf("Failed to process packet, ID=%d: %s", a.Session.ID(), err.Error())
+				return
+			}
+		}
+	}
+}
+
+// processPacket handles a packet read on the connection of a, it returns
+// the agent serving the connection afterwards, which is the resumed one
+// when a handshake resumes a previous session
+func (g *gateway) processPacket(a *agent, p *packet.Packet) (*agent, error) {
+	switch p.Type {
+	case packet.Handshake:
+		hd := &session.HandshakeData{}
+		if err := json.Unmarshal(p.Data, hd); err != nil {
+			return a, err
+		}
+		if r := g.resume(a, hd); r != nil {
+			return r, nil
+		}
+		a.Session.SetHandshakeData(hd)
+
+		hr := g.handshakeResponse(hd)
+		res, err := packet.EncodeHandshakeResponse(hr)
+		if err != nil {
+			return a, err
+		}
+		if err := a.writeAfterQueue(res); err != nil {
+			return a, err
+		}
+		// routes found by later refreshes are sent in full to this client
+		a.setDictionary(g.encoder, hr.Sys.Dict)
+		a.setStatus(constants.StatusHandshake)
+		logger.Debugf("Session handshake, ID=%d, Platform=%s", a.Session.ID(), hd.Sys.Platform)
+
+	case packet.HandshakeAck:
+		a.setStatus(constants.StatusWorking)
+		logger.Debugf("Receive handshake ACK, ID=%d, UID=%s", a.Session.ID(), a.Session.UID())
+
+	case packet.Data:
+		if a.status() < constants.StatusWorking {
+			return a, fmt.Errorf("receive data on session ID=%d before handshake ack", a.Session.ID())
+		}
+		msg, err := message.Decode(p.Data)
+		if err != nil {
+			return a, err
+		}
+		if !a.limiter.allow(msg.Route) {
+			g.rateLimited(a, msg)
+			return a, nil
+		}
+		a.receive(msg)
+
+	case packet.Heartbeat:
+		// lastTime was already refreshed when the packet was read, pomelo
+		// and starx clients still wait for the reply
+		a.heartbeat(g.heartbeat)
+
+	default:
+		return a, packet.ErrWrongPacketType
+	}
+	return a, nil
+}
+
+func (g *gateway) handshakeResponse(hd *session.HandshakeData) *packet.HandshakeResponse {
+	res := &packet.HandshakeResponse{
+		Code: packet.HandshakeOK,
+		Sys: packet.HandshakeSys{
+			Heartbeat:  int(g.opts.HeartbeatInterval.Seconds()),
+			Dict:       message.GetDictionary(),
+			Serializer: g.opts.Serializer.GetName(),
+		},
+	}
+
+	s := g.schema()
+	if s == nil || len(s.Routes) == 0 || s.Version == hd.Sys.ProtoVersion {
+		return res
+	}
+	protos, err := json.Marshal(s)
+	if err != nil {
+		logger.Errorf("Failed to encode protobuf schema: %s", err.Error())
+		return res
+	}
+	res.Sys.Protos = protos
+	return res
+}
+
+// dispatch forwards the messages of the client one at a time, so they
+// reach the backends in the order the client sent them
+func (g *gateway) dispatch(a *agent) {
+	for {
+		select {
+		case msg := <-a.chRecv:
+			g.processMessage(a, msg)
+		case <-a.chDie:
+			return
+		}
+	}
+}
+
+func (g *gateway) processMessage(a *agent, msg *message.Message) {
+	ctx := context.Background()
+	r, err := route.Decode(msg.Route)
+	if err != nil {
+		logger.Errorf("Failed to decode route: %s", err.Error())
+		g.responseError(ctx, a, msg, e.BadRequest(g.frontendID, "%s", err.Error()))
+		return
+	}
+	if r.SvType == "" {
+		g.responseError(ctx, a, msg, e.BadRequest(g.frontendID, "%s", constants.ErrNoServerTypeChosenForRPC.Error()))
+		return
+	}
+
+	ctx = util.BuildMcbContext(ctx, proto_mcbeam.RPCType_User, r, a.Session, msg, g.frontendID)
+	req, err := util.BuildRequest(ctx, proto_mcbeam.RPCType_User, r, a.Session, msg, g.frontendID)
+	if err != nil {
+		g.responseError(ctx, a, msg, e.InternalServerError(g.frontendID, "%s", err.Error()))
+		return
+	}
+
+	so := selector.WithStrategy(util.Select(r.SvID))
+	rsp, err := proto_mcbeam.NewMcbAppService(r.SvType, g.opts.Client).Call(ctx, req, client.WithSelectOption(so))
+	if err != nil {
+		logger.Errorf("Failed to process route %s: %s", r.String(), err.Error())
+		g.responseError(ctx, a, msg, e.Parse(err.Error()))
+		return
+	}
+	if msg.Type == message.Notify || rsp.GetDeferred() {
+		return
+	}
+	if err := a.ResponseMID(ctx, msg.ID, rsp.GetData()); err != nil {
+		logger.Errorf("Failed to respond route %s: %s", r.String(), err.Error())
+	}
+}
+
+func (g *gateway) responseError(ctx context.Context, a *agent, msg *message.Message, err error) {
+	if msg.Type != message.Request {
+		return
+	}
+	payload, perr := util.GetErrorPayload(a.serializer, err)
+	if perr != nil {
+		logger.Errorf("Failed to serialize error payload: %s", perr.Error())
+		return
+	}
+	if rerr := a.ResponseMID(ctx, msg.ID, payload, true); rerr != nil {
+		logger.Errorf("Failed to respond error: %s", rerr.Error())
+	}
+}
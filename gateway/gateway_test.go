@@ -0,0 +1,288 @@
+package gateway
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v2/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wolfplus2048/mcbeam-plus/constants"
+	"github.com/wolfplus2048/mcbeam-plus/message"
+	"github.com/wolfplus2048/mcbeam-plus/packet"
+	"github.com/wolfplus2048/mcbeam-plus/protos"
+	"github.com/wolfplus2048/mcbeam-plus/session"
+)
+
+// newTestGateway returns a gateway ready to handle connections, without
+// the rpc server and client Start needs
+func newTestGateway(t *testing.T, opts ...Option) *gateway {
+	g := &gateway{
+		opts:       newOptions(append([]Option{MessagesBufferSize(16)}, opts...)...),
+		frontendID: "gate-1",
+		encoder:    message.NewMessagesEncoder(false),
+		die:        make(chan struct{}),
+	}
+	hb, err := packet.Encode(packet.Heartbeat, nil)
+	require.NoError(t, err)
+	g.heartbeat = hb
+	require.NoError(t, g.initResume())
+	t.Cleanup(func() { close(g.die) })
+	return g
+}
+
+// testClient is the client end of a connection handled by the gateway
+type testClient struct {
+	t       *testing.T
+	conn    net.Conn
+	decoder *packet.PomeloDecoder
+	packets []*packet.Packet
+}
+
+// connect opens a loopback tcp connection served by the gateway, pipes
+// would block the gateway writes the test does not read
+func (g *gateway) connect(t *testing.T) *testClient {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	client, err := net.Dial("tcp", l.Addr().String())
+	require.NoError(t, err)
+	server, err := l.Accept()
+	require.NoError(t, err)
+	go g.handle(server)
+	t.Cleanup(func() { client.Close() })
+	return &testClient{t: t, conn: client, decoder: packet.NewPomeloDecoder()}
+}
+
+func (c *testClient) send(typ packet.Type, data []byte) {
+	p, err := packet.Encode(typ, data)
+	require.NoError(c.t, err)
+	c.conn.SetWriteDeadline(time.Now().Add(time.Second))
+	_, err = c.conn.Write(p)
+	require.NoError(c.t, err)
+}
+
+// read returns the next packet written by the gateway, or the read error
+func (c *testClient) read(timeout time.Duration) (*packet.Packet, error) {
+	buf := make([]byte, 1024)
+	for len(c.packets) == 0 {
+		c.conn.SetReadDeadline(time.Now().Add(timeout))
+		n, err := c.conn.Read(buf)
+		if err != nil {
+			return nil, err
+		}
+		packets, err := c.decoder.Decode(buf[:n])
+		require.NoError(c.t, err)
+		c.packets = append(c.packets, packets...)
+	}
+	p := c.packets[0]
+	c.packets = c.packets[1:]
+	return p, nil
+}
+
+// handshake runs the handshake of the client and returns its response
+func (c *testClient) handshake(hd string) *packet.Packet {
+	c.send(packet.Handshake, []byte(hd))
+	p, err := c.read(time.Second)
+	require.NoError(c.t, err)
+	require.Equal(c.t, packet.Type(packet.Handshake), p.Type)
+	c.send(packet.HandshakeAck, nil)
+	return p
+}
+
+// onlyAgent returns the agent of the single connection of the gateway
+func onlyAgent(t *testing.T, g *gateway) *agent {
+	var a *agent
+	require.Eventually(t, func() bool {
+		g.agents.Range(func(_, v interface{}) bool {
+			a = v.(*agent)
+			return false
+		})
+		return a != nil
+	}, time.Second, time.Millisecond)
+	return a
+}
+
+// onClose returns a channel getting the close reason of s
+func onClose(t *testing.T, s *session.Session) chan string {
+	closed := make(chan string, 1)
+	h := session.OnSessionClose(func(c *session.Session) {
+		if c == s {
+			closed <- c.CloseReason()
+		}
+	})
+	t.Cleanup(h.Remove)
+	return closed
+}
+
+func TestHeartbeatReply(t *testing.T) {
+	g := newTestGateway(t)
+	c := g.connect(t)
+	c.handshake(`{"sys":{"platform":"web"}}`)
+
+	c.send(packet.Heartbeat, nil)
+	p, err := c.read(time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, packet.Type(packet.Heartbeat), p.Type)
+}
+
+func TestHeartbeatEviction(t *testing.T) {
+	g := newTestGateway(t, Heartbeat(200*time.Millisecond), MaxMissedHeartbeats(1))
+	go g.sweep()
+
+	c := g.connect(t)
+	c.handshake(`{"sys":{"platform":"web"}}`)
+	closed := onClose(t, onlyAgent(t, g).Session)
+
+	// the client stays silent, the heartbeats it gets do not keep it alive
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		p, err := c.read(time.Until(deadline))
+		if err != nil {
+			break
+		}
+		assert.Equal(t, packet.Type(packet.Heartbeat), p.Type)
+	}
+	select {
+	case reason := <-closed:
+		assert.Equal(t, session.CloseReasonHeartbeatTimeout, reason)
+	case <-time.After(time.Second):
+		t.Fatal("session not closed")
+	}
+	assert.Equal(t, 0, countAgents(g))
+}
+
+func TestHeartbeatKeepsSessionAlive(t *testing.T) {
+	g := newTestGateway(t, Heartbeat(200*time.Millisecond), MaxMissedHeartbeats(1))
+	go g.sweep()
+
+	c := g.connect(t)
+	c.handshake(`{"sys":{"platform":"web"}}`)
+	for i := 0; i < 8; i++ {
+		c.send(packet.Heartbeat, nil)
+		time.Sleep(200 * time.Millisecond)
+	}
+	assert.Equal(t, 1, countAgents(g))
+	g.agents.Range(func(_, v interface{}) bool {
+		assert.Equal(t, constants.StatusWorking, v.(*agent).status())
+		return true
+	})
+}
+
+func countAgents(g *gateway) int {
+	n := 0
+	g.agents.Range(func(_, _ interface{}) bool {
+		n++
+		return true
+	})
+	return n
+}
+
+// publishMessage is a message of publishClient
+type publishMessage struct {
+	client.Message
+	topic   string
+	payload interface{}
+}
+
+func (m *publishMessage) Topic() string        { return m.topic }
+func (m *publishMessage) Payload() interface{} { return m.payload }
+
+// publishClient records the messages published by the gateway
+type publishClient struct {
+	client.Client
+	mutex     sync.Mutex
+	published []*publishMessage
+}
+
+func (c *publishClient) NewMessage(topic string, msg interface{}, opts ...client.MessageOption) client.Message {
+	return &publishMessage{topic: topic, payload: msg}
+}
+
+func (c *publishClient) Publish(ctx context.Context, msg client.Message, opts ...client.PublishOption) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.published = append(c.published, msg.(*publishMessage))
+	return nil
+}
+
+// topic returns the payloads published to topic
+func (c *publishClient) topic(topic string) []interface{} {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	var payloads []interface{}
+	for _, m := range c.published {
+		if m.topic == topic {
+			payloads = append(payloads, m.payload)
+		}
+	}
+	return payloads
+}
+
+func TestPublishSessionClose(t *testing.T) {
+	pc := &publishClient{}
+	g := newTestGateway(t, Client(pc))
+	c := g.connect(t)
+	c.handshake(`{"sys":{"platform":"web"}}`)
+	s := onlyAgent(t, g).Session
+
+	// backends keep nothing for sessions that were never bound
+	g.publishSessionClose(s)
+	assert.Empty(t, pc.topic(constants.SessionCloseTopic))
+
+	require.NoError(t, s.Bind(context.Background(), "u1"))
+	g.publishSessionClose(s)
+	assert.Equal(t, []interface{}{&proto_mcbeam.SessionClose{
+		Uid:        "u1",
+		FrontendID: "gate-1",
+		Id:         s.ID(),
+	}}, pc.topic(constants.SessionCloseTopic))
+}
+
+// appClient records the routes of the client messages forwarded to the
+// backends, the first call takes a while
+type appClient struct {
+	client.Client
+	mutex  sync.Mutex
+	routes []string
+}
+
+func (c *appClient) NewRequest(service, endpoint string, req interface{}, opts ...client.RequestOption) client.Request {
+	return client.NewClient().NewRequest(service, endpoint, req, opts...)
+}
+
+func (c *appClient) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	c.mutex.Lock()
+	first := len(c.routes) == 0
+	c.mutex.Unlock()
+	if first {
+		time.Sleep(50 * time.Millisecond)
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.routes = append(c.routes, req.Body().(*proto_mcbeam.Request).GetMsg().GetRoute())
+	return nil
+}
+
+func (c *appClient) called() []string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return append([]string(nil), c.routes...)
+}
+
+func TestDispatchInOrder(t *testing.T) {
+	ac := &appClient{}
+	g := newTestGateway(t, Client(ac))
+	c := g.connect(t)
+	c.handshake(`{"sys":{"platform":"web"}}`)
+
+	routes := []string{"room.table.join", "room.table.sit", "room.table.bet"}
+	for i, route := range routes {
+		c.request(uint(i+1), route)
+	}
+	require.Eventually(t, func() bool { return len(ac.called()) == len(routes) }, time.Second, time.Millisecond)
+	assert.Equal(t, routes, ac.called())
+}
@@ -0,0 +1,175 @@
+package gateway
+
+import (
+	"context"
+
+	e "github.com/micro/go-micro/v2/errors"
+	"github.com/micro/go-micro/v2/logger"
+	"github.com/wolfplus2048/mcbeam-plus/constants"
+	"github.com/wolfplus2048/mcbeam-plus/protos"
+	"github.com/wolfplus2048/mcbeam-plus/session"
+)
+
+// gateHandler serves the McbGate rpc so backend servers can reach the
+// sessions owned by this frontend
+type gateHandler struct {
+	name string
+	gate *gateway
+}
+
+// Push pushes a message to the session bound to the uid
+func (h *gateHandler) Push(ctx context.Context, in *proto_mcbeam.PushMsg, out *proto_mcbeam.Response) error {
+	s := session.GetSessionByUID(in.GetUid())
+	if s == nil {
+		return e.NotFound(h.name, "%s, uid: %s", constants.ErrSessionNotFound.Error(), in.GetUid())
+	}
+	if err := s.Push(in.GetRoute(), in.GetData()); err != nil {
+		return e.InternalServerError(h.name, "%s", err.Error())
+	}
+	return nil
+}
+
+// PushSession replaces the frontend session data with the one sent by a backend
+func (h *gateHandler) PushSession(ctx context.Context, in *proto_mcbeam.Session, out *proto_mcbeam.Response) error {
+	s := session.GetSessionByID(in.GetId())
+	if s == nil {
+		return e.NotFound(h.name, "%s, id: %d", constants.ErrSessionNotFound.Error(), in.GetId())
+	}
+	if err := s.SetDataEncoded(in.GetData()); err != nil {
+		return e.BadRequest(h.name, "%s", err.Error())
+	}
+	return nil
+}
+
+// PushSessionDelta applies the changes a backend made to the frontend
+// session data, they are rejected with a conflict when stale
+func (h *gateHandler) PushSessionDelta(ctx context.Context, in *proto_mcbeam.SessionDelta, out *proto_mcbeam.SessionDeltaAnswer) error {
+	s := session.GetSessionByID(in.GetId())
+	if s == nil {
+		return e.NotFound(h.name, "%s, id: %d", constants.ErrSessionNotFound.Error(), in.GetId())
+	}
+	merge := !h.gate.opts.StrictSessionVersion
+	version, err := s.ApplyDelta(in.GetVersion(), in.GetData(), in.GetRemoved(), merge)
+	if err == constants.ErrStaleSessionData {
+		return e.Conflict(h.name, "%s, id: %d", err.Error(), in.GetId())
+	}
+	if err != nil {
+		return e.BadRequest(h.name, "%s", err.Error())
+	}
+	out.Version = version
+	return nil
+}
+
+// GetSession returns the session data to a backend whose cached copy is
+// stale
+func (h *gateHandler) GetSession(ctx context.Context, in *proto_mcbeam.Session, out *proto_mcbeam.Session) error {
+	s := session.GetSessionByID(in.GetId())
+	if s == nil {
+		return e.NotFound(h.name, "%s, id: %d", constants.ErrSessionNotFound.Error(), in.GetId())
+	}
+	out.Id = s.ID()
+	out.Uid = s.UID()
+	out.Data, out.Version = s.Snapshot()
+	return nil
+}
+
+// Bind binds the uid to the frontend session
+func (h *gateHandler) Bind(ctx context.Context, in *proto_mcbeam.Session, out *proto_mcbeam.Response) error {
+	s := session.GetSessionByID(in.GetId())
+	if s == nil {
+		return e.NotFound(h.name, "%s, id: %d", constants.ErrSessionNotFound.Error(), in.GetId())
+	}
+	if err := s.Bind(ctx, in.GetUid()); err != nil {
+		return e.BadRequest(h.name, "%s", err.Error())
+	}
+	h.gate.issueResumeToken(s)
+	return nil
+}
+
+// ResponseMID responds a client request with the reply a backend sent
+// after its handler returned
+func (h *gateHandler) ResponseMID(ctx context.Context, in *proto_mcbeam.ResponseMsg, out *proto_mcbeam.Response) error {
+	s := session.GetSessionByID(in.GetSessionId())
+	if s == nil {
+		return e.NotFound(h.name, "%s, id: %d", constants.ErrSessionNotFound.Error(), in.GetSessionId())
+	}
+	if err := s.ResponseMID(ctx, uint(in.GetMid()), in.GetData(), in.GetError()); err != nil {
+		return e.InternalServerError(h.name, "%s", err.Error())
+	}
+	return nil
+}
+
+// Kick kicks the session bound to the uid
+func (h *gateHandler) Kick(ctx context.Context, in *proto_mcbeam.KickMsg, out *proto_mcbeam.KickAnswer) error {
+	s := session.GetSessionByUID(in.GetUserId())
+	if s == nil {
+		return e.NotFound(h.name, "%s, uid: %s", constants.ErrSessionNotFound.Error(), in.GetUserId())
+	}
+	if err := s.Kick(ctx); err != nil {
+		return e.InternalServerError(h.name, "%s", err.Error())
+	}
+	out.Kicked = true
+	return nil
+}
+
+// PushToUsers pushes the message to the sessions bound to the uids, the
+// uids with no session on this frontend are answered as failed
+func (h *gateHandler) PushToUsers(ctx context.Context, in *proto_mcbeam.PushToUsersMsg, out *proto_mcbeam.UsersAnswer) error {
+	for _, uid := range in.GetUids() {
+		s := session.GetSessionByUID(uid)
+		if s == nil {
+			out.FailedUids = append(out.FailedUids, uid)
+			continue
+		}
+		if err := s.Push(in.GetRoute(), in.GetData()); err != nil {
+			logger.Errorf("Failed to push to uid %s: %s", uid, err.Error())
+			out.FailedUids = append(out.FailedUids, uid)
+		}
+	}
+	return nil
+}
+
+// PushBatch pushes the messages a backend buffered in the order they were
+// sent, the uids with a failed push are answered once
+func (h *gateHandler) PushBatch(ctx context.Context, in *proto_mcbeam.PushBatchMsg, out *proto_mcbeam.UsersAnswer) error {
+	failed := make(map[string]bool)
+	for _, push := range in.GetPushes() {
+		uid := push.GetUid()
+		s := session.GetSessionByUID(uid)
+		if s == nil {
+			if !failed[uid] {
+				failed[uid] = true
+				out.FailedUids = append(out.FailedUids, uid)
+			}
+			continue
+		}
+		if err := s.Push(push.GetRoute(), push.GetData()); err != nil {
+			logger.Errorf("Failed to push to uid %s: %s", uid, err.Error())
+			if !failed[uid] {
+				failed[uid] = true
+				out.FailedUids = append(out.FailedUids, uid)
+			}
+		}
+	}
+	return nil
+}
+
+// KickUsers kicks the sessions bound to the uids, the reason is sent to
+// the clients in the kick packet
+func (h *gateHandler) KickUsers(ctx context.Context, in *proto_mcbeam.KickUsersMsg, out *proto_mcbeam.UsersAnswer) error {
+	for _, uid := range in.GetUids() {
+		s := session.GetSessionByUID(uid)
+		if s == nil {
+			out.FailedUids = append(out.FailedUids, uid)
+			continue
+		}
+		if in.GetReason() != "" {
+			s.SetCloseReason(in.GetReason())
+		}
+		if err := s.Kick(ctx); err != nil {
+			logger.Errorf("Failed to kick uid %s: %s", uid, err.Error())
+			out.FailedUids = append(out.FailedUids, uid)
+		}
+	}
+	return nil
+}
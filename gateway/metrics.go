@@ -0,0 +1,24 @@
+package gateway
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	sendQueueDepth = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "mcbeam",
+		Subsystem: "gateway",
+		Name:      "send_queue_depth",
+		Help:      "the number of messages waiting to be written to clients",
+	})
+	sendBufferOverflows = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "mcbeam",
+		Subsystem: "gateway",
+		Name:      "send_buffer_overflows_total",
+		Help:      "the number of messages that overflowed a session send buffer",
+	}, []string{"policy"})
+)
+
+func init() {
+	prometheus.MustRegister(sendQueueDepth, sendBufferOverflows)
+}
@@ -0,0 +1,230 @@
+package gateway
+
+import (
+	"time"
+
+	"github.com/micro/go-micro/v2/client"
+	"github.com/micro/go-micro/v2/registry"
+	"github.com/micro/go-micro/v2/server"
+	"github.com/wolfplus2048/mcbeam-plus/acceptor"
+	"github.com/wolfplus2048/mcbeam-plus/ratelimit"
+	"github.com/wolfplus2048/mcbeam-plus/serialize"
+	"github.com/wolfplus2048/mcbeam-plus/serialize/protobuf"
+)
+
+// BufferPolicy is what a session does when its send buffer is full
+type BufferPolicy int
+
+const (
+	// BufferDropNewest drops the message being sent and returns
+	// constants.ErrBufferExceed to the sender
+	BufferDropNewest BufferPolicy = iota
+	// BufferDropOldest drops the oldest queued message to make room
+	BufferDropOldest
+	// BufferKick kicks the session, its client is too slow to keep up
+	BufferKick
+)
+
+func (p BufferPolicy) String() string {
+	switch p {
+	case BufferDropOldest:
+		return "drop_oldest"
+	case BufferKick:
+		return "kick"
+	default:
+		return "drop_newest"
+	}
+}
+
+type Options struct {
+	// Address of the default tcp acceptor, used when no acceptor is set
+	Address string
+	// Acceptors listening for client connections
+	Acceptors []acceptor.Acceptor
+	// Client used to forward client messages to backend servers
+	Client client.Client
+	// Server the McbGate handler is registered on
+	Server server.Server
+	// Registry the routes of the cluster servers are read from, the
+	// registry of Client by default
+	Registry registry.Registry
+	// Serializer used for pushes and error payloads sent to clients
+	Serializer      serialize.Serializer
+	DataCompression bool
+	// HeartbeatInterval between heartbeats sent to clients, zero disables
+	// heartbeats and idle sessions eviction
+	HeartbeatInterval time.Duration
+	// MaxMissedHeartbeats is the number of heartbeat intervals a client can
+	// stay silent before its session is closed
+	MaxMissedHeartbeats int
+	// ResumeGrace is how long the session of a bound client that lost its
+	// connection is kept for the client to resume it, zero disables resume
+	ResumeGrace time.Duration
+	// ResumeBufferSize is the max number of packets kept for a client
+	// during the grace window, the oldest ones are dropped first
+	ResumeBufferSize int
+	// MessagesBufferSize is the size of each session send buffer,
+	// DefaultMessagesBufferSize by default
+	MessagesBufferSize int
+	// BufferPolicy applied when a session send buffer is full
+	BufferPolicy BufferPolicy
+	// RateLimit of the messages each client sends, zero rate disables it
+	RateLimit ratelimit.Limit
+	// RouteRateLimits of the messages each client sends by route
+	RouteRateLimits map[string]ratelimit.Limit
+	// RateLimitKick is the number of messages rejected in a row a client
+	// is kicked after, zero never kicks
+	RateLimitKick int
+	// StrictSessionVersion rejects the session changes of a backend when
+	// the session changed since the backend read it, by default they are
+	// merged when none of their keys changed
+	StrictSessionVersion bool
+}
+type Option func(o *Options)
+
+// DefaultMessagesBufferSize is the size of the session send buffers
+const DefaultMessagesBufferSize = 100
+
+func newOptions(opt ...Option) Options {
+	opts := Options{
+		Serializer:          protobuf.NewSerializer(),
+		MaxMissedHeartbeats: 2,
+		ResumeBufferSize:    100,
+		MessagesBufferSize:  DefaultMessagesBufferSize,
+	}
+	for _, o := range opt {
+		o(&opts)
+	}
+	return opts
+}
+
+// Address to listen on for client connections
+func Address(addr string) Option {
+	return func(o *Options) {
+		o.Address = addr
+	}
+}
+
+// WithAcceptor adds an acceptor clients can connect through, e.g.
+// acceptor.NewWSAcceptor for browsers and acceptor.NewTCPAcceptor for
+// native clients
+func WithAcceptor(a acceptor.Acceptor) Option {
+	return func(o *Options) {
+		o.Acceptors = append(o.Acceptors, a)
+	}
+}
+
+// Client used to reach backend servers
+func Client(c client.Client) Option {
+	return func(o *Options) {
+		o.Client = c
+	}
+}
+
+// Server the frontend is running on
+func Server(s server.Server) Option {
+	return func(o *Options) {
+		o.Server = s
+	}
+}
+
+// Registry the routes of the cluster servers are read from
+func Registry(r registry.Registry) Option {
+	return func(o *Options) {
+		o.Registry = r
+	}
+}
+
+func Serializer(s serialize.Serializer) Option {
+	return func(o *Options) {
+		o.Serializer = s
+	}
+}
+
+// DataCompression enables deflate compression of message payloads
+func DataCompression(b bool) Option {
+	return func(o *Options) {
+		o.DataCompression = b
+	}
+}
+
+// Heartbeat sets the interval between heartbeats
+func Heartbeat(d time.Duration) Option {
+	return func(o *Options) {
+		o.HeartbeatInterval = d
+	}
+}
+
+// MaxMissedHeartbeats sets how many heartbeat intervals a client can miss
+// before being disconnected
+func MaxMissedHeartbeats(n int) Option {
+	return func(o *Options) {
+		o.MaxMissedHeartbeats = n
+	}
+}
+
+// Resume lets clients resume their session within the grace window after
+// losing their connection, the token to resume it is pushed to the client
+// on ResumeRoute once the session is bound
+func Resume(grace time.Duration) Option {
+	return func(o *Options) {
+		o.ResumeGrace = grace
+	}
+}
+
+// ResumeBufferSize sets how many packets are kept for a client that lost
+// its connection until it resumes the session
+func ResumeBufferSize(n int) Option {
+	return func(o *Options) {
+		o.ResumeBufferSize = n
+	}
+}
+
+// MessagesBufferSize sets the size of each session send buffer
+func MessagesBufferSize(n int) Option {
+	return func(o *Options) {
+		o.MessagesBufferSize = n
+	}
+}
+
+// WithBufferPolicy sets what a session does when its send buffer is full
+func WithBufferPolicy(p BufferPolicy) Option {
+	return func(o *Options) {
+		o.BufferPolicy = p
+	}
+}
+
+// RateLimit limits the messages each client sends to rate per second, with
+// bursts of up to burst messages
+func RateLimit(rate float64, burst int) Option {
+	return func(o *Options) {
+		o.RateLimit = ratelimit.Limit{Rate: rate, Burst: burst}
+	}
+}
+
+// RouteRateLimit limits the messages each client sends to the route, e.g.
+// RouteRateLimit("chat.room.send", 2, 2)
+func RouteRateLimit(route string, rate float64, burst int) Option {
+	return func(o *Options) {
+		if o.RouteRateLimits == nil {
+			o.RouteRateLimits = make(map[string]ratelimit.Limit)
+		}
+		o.RouteRateLimits[route] = ratelimit.Limit{Rate: rate, Burst: burst}
+	}
+}
+
+// RateLimitKick kicks clients once n of their messages in a row were
+// rejected by the rate limits
+func RateLimitKick(n int) Option {
+	return func(o *Options) {
+		o.RateLimitKick = n
+	}
+}
+
+// StrictSessionVersion rejects the session changes a backend pushes when
+// any key of the session changed since the backend read it
+func StrictSessionVersion(b bool) Option {
+	return func(o *Options) {
+		o.StrictSessionVersion = b
+	}
+}
@@ -0,0 +1,73 @@
+package gateway
+
+import (
+	"context"
+
+	e "github.com/micro/go-micro/v2/errors"
+	"github.com/micro/go-micro/v2/logger"
+	"github.com/wolfplus2048/mcbeam-plus/constants"
+	"github.com/wolfplus2048/mcbeam-plus/message"
+	"github.com/wolfplus2048/mcbeam-plus/ratelimit"
+	"github.com/wolfplus2048/mcbeam-plus/session"
+)
+
+// limiter throttles the messages of a client, it is only used by the
+// goroutine reading the client connection
+type limiter struct {
+	session  *ratelimit.Bucket
+	limits   map[string]ratelimit.Limit
+	routes   map[string]*ratelimit.Bucket
+	rejected int // messages rejected in a row
+}
+
+// newLimiter returns nil when no limit is set
+func newLimiter(opts Options) *limiter {
+	if opts.RateLimit.Rate <= 0 && len(opts.RouteRateLimits) == 0 {
+		return nil
+	}
+	l := &limiter{
+		limits: opts.RouteRateLimits,
+		routes: make(map[string]*ratelimit.Bucket),
+	}
+	if opts.RateLimit.Rate > 0 {
+		l.session = ratelimit.NewBucket(opts.RateLimit)
+	}
+	return l
+}
+
+// allow takes a token from the session and route buckets, a message
+// rejected by one of them does not use the tokens of the other
+func (l *limiter) allow(route string) bool {
+	if l == nil {
+		return true
+	}
+	var b *ratelimit.Bucket
+	if limit, ok := l.limits[route]; ok {
+		if b, ok = l.routes[route]; !ok {
+			b = ratelimit.NewBucket(limit)
+			l.routes[route] = b
+		}
+	}
+	if !ratelimit.AllowAll(l.session, b) {
+		l.rejected++
+		return false
+	}
+	l.rejected = 0
+	return true
+}
+
+// rateLimited answers a throttled request with a 429 error, and kicks the
+// client once it got RateLimitKick messages rejected in a row
+func (g *gateway) rateLimited(a *agent, msg *message.Message) {
+	logger.Warnf("Rate limit exceeded, ID=%d, UID=%s, Route=%s", a.Session.ID(), a.Session.UID(), msg.Route)
+	ctx := context.Background()
+	g.responseError(ctx, a, msg, e.New(g.frontendID, constants.ErrRateLimitExceeded.Error(), 429))
+
+	if g.opts.RateLimitKick > 0 && a.limiter.rejected >= g.opts.RateLimitKick {
+		logger.Warnf("Kicking session over rate limit, ID=%d, UID=%s", a.Session.ID(), a.Session.UID())
+		a.Session.SetCloseReason(session.CloseReasonRateLimit)
+		if err := a.Session.Kick(ctx); err != nil {
+			logger.Errorf("Failed to kick session, ID=%d: %s", a.Session.ID(), err.Error())
+		}
+	}
+}
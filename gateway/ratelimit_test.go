@@ -0,0 +1,59 @@
+package gateway
+
+import (
+	"testing"
+	"time"
+
+	e "github.com/micro/go-micro/v2/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wolfplus2048/mcbeam-plus/message"
+	"github.com/wolfplus2048/mcbeam-plus/packet"
+	"github.com/wolfplus2048/mcbeam-plus/serialize/protobuf"
+	"github.com/wolfplus2048/mcbeam-plus/session"
+)
+
+func TestLimiterRejectedInARow(t *testing.T) {
+	l := newLimiter(newOptions(RateLimit(1000, 2), RouteRateLimit("chat.room.send", 0.001, 1)))
+
+	assert.True(t, l.allow("chat.room.send"))
+	assert.False(t, l.allow("chat.room.send"))
+	assert.False(t, l.allow("chat.room.send"))
+	assert.Equal(t, 2, l.rejected)
+
+	// the rejected messages did not use the session tokens
+	assert.True(t, l.allow("room.table.join"))
+	assert.Equal(t, 0, l.rejected)
+}
+
+func (c *testClient) request(id uint, route string) {
+	m, err := message.NewMessagesEncoder(false).Encode(&message.Message{Type: message.Request, ID: id, Route: route, Data: []byte{}})
+	require.NoError(c.t, err)
+	c.send(packet.Data, m)
+}
+
+func TestRateLimitKick(t *testing.T) {
+	// the route takes no message at all, none is forwarded to a backend
+	g := newTestGateway(t, RouteRateLimit("chat.room.send", 1, 0), RateLimitKick(2))
+	c := g.connect(t)
+	c.handshake(`{"sys":{"platform":"web"}}`)
+	closed := onClose(t, onlyAgent(t, g).Session)
+
+	for id := uint(1); id <= 2; id++ {
+		c.request(id, "chat.room.send")
+		m := c.push()
+		assert.Equal(t, message.Response, m.Type)
+		assert.Equal(t, id, m.ID)
+		assert.True(t, m.Err)
+		rerr := &e.Error{}
+		require.NoError(t, protobuf.NewSerializer().Unmarshal(m.Data, rerr))
+		assert.Equal(t, int32(429), rerr.Code)
+	}
+	assert.Equal(t, session.CloseReasonRateLimit, c.kicked())
+	select {
+	case reason := <-closed:
+		assert.Equal(t, session.CloseReasonRateLimit, reason)
+	case <-time.After(time.Second):
+		t.Fatal("session not closed")
+	}
+}
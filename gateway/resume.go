@@ -0,0 +1,182 @@
+package gateway
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/micro/go-micro/v2/logger"
+	"github.com/wolfplus2048/mcbeam-plus/constants"
+	"github.com/wolfplus2048/mcbeam-plus/packet"
+	"github.com/wolfplus2048/mcbeam-plus/session"
+)
+
+// ResumeRoute is the route resume tokens are pushed to clients on, the
+// client sends the last token it got in the handshake sys.resumeToken
+// field when reconnecting
+const ResumeRoute = "sys.resume"
+
+func (g *gateway) initResume() error {
+	g.parked = make(map[int64]*agent)
+	g.resumeKey = make([]byte, 32)
+	_, err := rand.Read(g.resumeKey)
+	return err
+}
+
+// issueResumeToken pushes a new resume token to the client of a bound
+// session, tokens issued before it can no longer be used
+func (g *gateway) issueResumeToken(s *session.Session) {
+	if g.opts.ResumeGrace <= 0 {
+		return
+	}
+	v, ok := g.agents.Load(s.ID())
+	if !ok {
+		return
+	}
+	a := v.(*agent)
+	nonce := time.Now().UnixNano()
+	atomic.StoreInt64(&a.resumeNonce, nonce)
+
+	if err := a.Push(ResumeRoute, []byte(g.resumeToken(s.ID(), nonce))); err != nil {
+		logger.Errorf("Failed to push resume token, ID=%d: %s", s.ID(), err.Error())
+	}
+}
+
+// resumeToken returns the signed token of the session id and nonce, the
+// payload is only encoded so the uid is left out of it, the session the
+// token resumes gives the uid
+func (g *gateway) resumeToken(id, nonce int64) string {
+	payload := []byte(fmt.Sprintf("%d.%d", id, nonce))
+	return base64.RawURLEncoding.EncodeToString(payload) + "." +
+		base64.RawURLEncoding.EncodeToString(g.sign(payload))
+}
+
+func (g *gateway) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, g.resumeKey)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+func (g *gateway) parseResumeToken(token string) (id, nonce int64, err error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 {
+		return 0, 0, constants.ErrInvalidResumeToken
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return 0, 0, constants.ErrInvalidResumeToken
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil || !hmac.Equal(sig, g.sign(payload)) {
+		return 0, 0, constants.ErrInvalidResumeToken
+	}
+	fields := strings.Split(string(payload), ".")
+	if len(fields) != 2 {
+		return 0, 0, constants.ErrInvalidResumeToken
+	}
+	if id, err = strconv.ParseInt(fields[0], 10, 64); err != nil {
+		return 0, 0, constants.ErrInvalidResumeToken
+	}
+	if nonce, err = strconv.ParseInt(fields[1], 10, 64); err != nil {
+		return 0, 0, constants.ErrInvalidResumeToken
+	}
+	return id, nonce, nil
+}
+
+// release is called once conn is lost, it returns false when the session
+// of a must be closed. The session is kept when it was resumed on another
+// connection, or parked when the client can still resume it.
+func (g *gateway) release(a *agent, conn net.Conn) bool {
+	g.resumeMutex.Lock()
+	defer g.resumeMutex.Unlock()
+	if !a.owns(conn) {
+		return true
+	}
+	g.agents.Delete(a.Session.ID())
+	if g.opts.ResumeGrace <= 0 || a.Session.UID() == "" || a.status() != constants.StatusWorking {
+		return false
+	}
+
+	a.detach()
+	g.parked[a.Session.ID()] = a
+	reason := a.closeReason()
+	time.AfterFunc(g.opts.ResumeGrace, func() {
+		g.resumeMutex.Lock()
+		if g.parked[a.Session.ID()] != a {
+			g.resumeMutex.Unlock()
+			return
+		}
+		delete(g.parked, a.Session.ID())
+		g.resumeMutex.Unlock()
+
+		logger.Debugf("Session resume window expired, ID=%d, UID=%s", a.Session.ID(), a.Session.UID())
+		a.Session.SetCloseReason(reason)
+		a.Session.Close()
+	})
+	logger.Debugf("Session parked, ID=%d, UID=%s", a.Session.ID(), a.Session.UID())
+	return true
+}
+
+// resume moves the connection of a to the session the handshake resume
+// token was issued for and replays the packets the client missed. It
+// returns nil when there is no session to resume, a then goes on with
+// a regular handshake.
+func (g *gateway) resume(a *agent, hd *session.HandshakeData) *agent {
+	if g.opts.ResumeGrace <= 0 || hd.Sys.ResumeToken == "" {
+		return nil
+	}
+	id, nonce, err := g.parseResumeToken(hd.Sys.ResumeToken)
+	if err != nil {
+		logger.Debugf("Failed to resume session, ID=%d: %s", a.Session.ID(), err.Error())
+		return nil
+	}
+
+	g.resumeMutex.Lock()
+	r, parked := g.parked[id]
+	if !parked {
+		// the previous connection may be half-open and not timed out yet
+		if v, ok := g.agents.Load(id); ok {
+			r = v.(*agent)
+		}
+	}
+	// the nonce is only set on bound sessions and changes with every token
+	if r == nil || r == a || atomic.LoadInt64(&r.resumeNonce) != nonce || r.status() == constants.StatusClosed {
+		g.resumeMutex.Unlock()
+		logger.Debugf("Failed to resume session, ID=%d: no session %d for the token", a.Session.ID(), id)
+		return nil
+	}
+	uid := r.Session.UID()
+
+	res := g.handshakeResponse(hd)
+	res.Sys.Resumed = true
+	p, err := packet.EncodeHandshakeResponse(res)
+	if err != nil {
+		g.resumeMutex.Unlock()
+		logger.Errorf("Failed to encode handshake response: %s", err.Error())
+		return nil
+	}
+
+	delete(g.parked, id)
+	g.agents.Delete(a.Session.ID())
+	g.agents.Store(id, r)
+	conn := a.detach()
+	r.Session.SetHandshakeData(hd)
+	r.Session.Touch()
+	if err := r.attach(conn, p); err != nil {
+		logger.Debugf("Failed to replay packets, ID=%d: %s", id, err.Error())
+	}
+	r.setDictionary(g.encoder, res.Sys.Dict)
+	g.resumeMutex.Unlock()
+
+	logger.Debugf("Session resumed, ID=%d, UID=%s, IP=%s", id, uid, conn.RemoteAddr())
+	a.Session.Close()
+	g.issueResumeToken(r.Session)
+	return r
+}
@@ -0,0 +1,175 @@
+package gateway
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wolfplus2048/mcbeam-plus/constants"
+	"github.com/wolfplus2048/mcbeam-plus/message"
+	"github.com/wolfplus2048/mcbeam-plus/packet"
+	"github.com/wolfplus2048/mcbeam-plus/protos"
+	"github.com/wolfplus2048/mcbeam-plus/session"
+)
+
+func TestParseResumeToken(t *testing.T) {
+	g := newTestGateway(t)
+	token := g.resumeToken(7, 42)
+	signed := func(payload string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." +
+			base64.RawURLEncoding.EncodeToString(g.sign([]byte(payload)))
+	}
+	// the signature of another nonce
+	sig := base64.RawURLEncoding.EncodeToString(g.sign([]byte("7.43")))
+
+	tables := []struct {
+		name  string
+		token string
+		id    int64
+		nonce int64
+		err   error
+	}{
+		{"round trip", token, 7, 42, nil},
+		{"tampered payload", base64.RawURLEncoding.EncodeToString([]byte("8.42")) + token[strings.Index(token, "."):], 0, 0, constants.ErrInvalidResumeToken},
+		{"tampered signature", token[:strings.Index(token, ".")+1] + sig, 0, 0, constants.ErrInvalidResumeToken},
+		{"truncated", token[:len(token)/2], 0, 0, constants.ErrInvalidResumeToken},
+		{"one part", "Ny40Mg", 0, 0, constants.ErrInvalidResumeToken},
+		{"three parts", token + ".AA", 0, 0, constants.ErrInvalidResumeToken},
+		{"signed with uid", signed("7.42.u1"), 0, 0, constants.ErrInvalidResumeToken},
+		{"signed bad id", signed("x.42"), 0, 0, constants.ErrInvalidResumeToken},
+		{"other key", newTestGateway(t).resumeToken(7, 42), 0, 0, constants.ErrInvalidResumeToken},
+	}
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			id, nonce, err := g.parseResumeToken(table.token)
+			assert.Equal(t, table.err, err)
+			assert.Equal(t, table.id, id)
+			assert.Equal(t, table.nonce, nonce)
+		})
+	}
+}
+
+// bind binds the session of the client connection as a backend would and
+// returns the resume token the client got
+func bind(t *testing.T, g *gateway, c *testClient, uid string) string {
+	a := onlyAgent(t, g)
+	h := &gateHandler{name: "gate", gate: g}
+	require.NoError(t, h.Bind(context.Background(), &proto_mcbeam.Session{Id: a.Session.ID(), Uid: uid}, &proto_mcbeam.Response{}))
+	return c.token()
+}
+
+// token reads the pushes until the resume token
+func (c *testClient) token() string {
+	for {
+		m := c.push()
+		if m.Route == ResumeRoute {
+			return string(m.Data)
+		}
+	}
+}
+
+func (c *testClient) push() *message.Message {
+	p, err := c.read(time.Second)
+	require.NoError(c.t, err)
+	require.Equal(c.t, packet.Type(packet.Data), p.Type)
+	m, err := message.Decode(p.Data)
+	require.NoError(c.t, err)
+	return m
+}
+
+// resumed runs the handshake of the token and tells whether it resumed
+func (c *testClient) resumed(token string) bool {
+	p := c.handshake(fmt.Sprintf(`{"sys":{"platform":"web","resumeToken":%q}}`, token))
+	res := &packet.HandshakeResponse{}
+	require.NoError(c.t, json.Unmarshal(p.Data, res))
+	return res.Sys.Resumed
+}
+
+// parked waits until the session of a waits to be resumed
+func parked(t *testing.T, g *gateway, a *agent) {
+	require.Eventually(t, func() bool {
+		g.resumeMutex.Lock()
+		defer g.resumeMutex.Unlock()
+		return g.parked[a.Session.ID()] == a
+	}, time.Second, time.Millisecond)
+}
+
+func TestResume(t *testing.T) {
+	tables := []struct {
+		name    string
+		stale   bool // the client uses the token issued before the last one
+		expire  bool // the client comes back after the grace window
+		resumed bool
+	}{
+		{"last token", false, false, true},
+		{"stale nonce after re-bind", true, false, false},
+		{"grace expired", false, true, false},
+	}
+	for i, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			g := newTestGateway(t, Resume(200*time.Millisecond))
+			c := g.connect(t)
+			c.handshake(`{"sys":{"platform":"web"}}`)
+			token := bind(t, g, c, fmt.Sprintf("resume-%d", i))
+			a := onlyAgent(t, g)
+			closed := onClose(t, a.Session)
+			// binding again issues a new token
+			g.issueResumeToken(a.Session)
+			if last := c.token(); !table.stale {
+				token = last
+			}
+
+			c.conn.Close()
+			parked(t, g, a)
+			if table.expire {
+				select {
+				case reason := <-closed:
+					assert.Equal(t, session.CloseReasonDisconnect, reason)
+				case <-time.After(time.Second):
+					t.Fatal("session not closed")
+				}
+			}
+
+			c = g.connect(t)
+			assert.Equal(t, table.resumed, c.resumed(token))
+			if table.resumed {
+				assert.Equal(t, a, onlyAgent(t, g))
+				// the client gets a new token for the next time
+				assert.NotEqual(t, token, c.token())
+			}
+		})
+	}
+}
+
+func TestResumeReplaysPending(t *testing.T) {
+	g := newTestGateway(t, Resume(time.Second), ResumeBufferSize(3))
+	c := g.connect(t)
+	c.handshake(`{"sys":{"platform":"web"}}`)
+	token := bind(t, g, c, "resume-pending")
+	a := onlyAgent(t, g)
+
+	c.conn.Close()
+	parked(t, g, a)
+	for i := 0; i < 5; i++ {
+		require.NoError(t, a.Push(fmt.Sprintf("room.tick%d", i), []byte("tick")))
+	}
+	require.Eventually(t, func() bool {
+		a.writeMutex.Lock()
+		defer a.writeMutex.Unlock()
+		return len(a.pending) == 3 && len(a.chSend) == 0
+	}, time.Second, time.Millisecond)
+
+	c = g.connect(t)
+	require.True(t, c.resumed(token))
+	// the oldest packets were dropped, the others come in order
+	for i := 2; i < 5; i++ {
+		assert.Equal(t, fmt.Sprintf("room.tick%d", i), c.push().Route)
+	}
+	assert.Equal(t, ResumeRoute, c.push().Route)
+}
@@ -0,0 +1,98 @@
+package gateway
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/micro/go-micro/v2/logger"
+	"github.com/wolfplus2048/mcbeam-plus/constants"
+	"github.com/wolfplus2048/mcbeam-plus/message"
+	"github.com/wolfplus2048/mcbeam-plus/schema"
+)
+
+// routesRefreshInterval is how often the routes of the servers joining
+// the cluster are added to the dictionary and schema
+var routesRefreshInterval = 10 * time.Second
+
+// loadRoutes adds the routes the servers of the cluster publish in their
+// registry metadata to the message dictionary, and merges their schemas
+// into the one sent to clients on handshake. Routes keep their code once
+// given, so routes of servers that left stay in the dictionary.
+func (g *gateway) loadRoutes() error {
+	var metadata []map[string]string
+	if g.opts.Server != nil {
+		// the own node of the gateway is not registered yet on start
+		metadata = append(metadata, g.opts.Server.Options().Metadata)
+	}
+	if g.opts.Registry != nil {
+		services, err := g.opts.Registry.ListServices()
+		if err != nil {
+			return err
+		}
+		sort.Slice(services, func(i, j int) bool { return services[i].Name < services[j].Name })
+		for _, svc := range services {
+			versions, err := g.opts.Registry.GetService(svc.Name)
+			if err != nil {
+				return err
+			}
+			for _, v := range versions {
+				nodes := v.Nodes
+				sort.Slice(nodes, func(i, j int) bool { return nodes[i].Id < nodes[j].Id })
+				for _, node := range nodes {
+					metadata = append(metadata, node.Metadata)
+				}
+			}
+		}
+	}
+
+	var routes []string
+	schemas := make([]*schema.Schema, 0, len(metadata))
+	for _, md := range metadata {
+		if data, ok := md[constants.RoutesMetadataKey]; ok {
+			var rs []string
+			if err := json.Unmarshal([]byte(data), &rs); err != nil {
+				return err
+			}
+			routes = append(routes, rs...)
+		}
+		if data, ok := md[constants.SchemaMetadataKey]; ok {
+			s := &schema.Schema{}
+			if err := json.Unmarshal([]byte(data), s); err != nil {
+				return err
+			}
+			schemas = append(schemas, s)
+		}
+	}
+	if err := message.AddRoutes(routes...); err != nil {
+		return err
+	}
+	s, err := schema.Merge(append([]*schema.Schema{g.schema()}, schemas...)...)
+	if err != nil {
+		return err
+	}
+	g.clusterSchema.Store(s)
+	return nil
+}
+
+// schema returns the schema of the cluster routes loaded so far
+func (g *gateway) schema() *schema.Schema {
+	s, _ := g.clusterSchema.Load().(*schema.Schema)
+	return s
+}
+
+// refreshRoutes loads the routes of the cluster until the gateway stops
+func (g *gateway) refreshRoutes() {
+	ticker := time.NewTicker(routesRefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := g.loadRoutes(); err != nil {
+				logger.Errorf("Failed to load cluster routes: %s", err.Error())
+			}
+		case <-g.die:
+			return
+		}
+	}
+}
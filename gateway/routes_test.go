@@ -0,0 +1,119 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v2/registry"
+	"github.com/micro/go-micro/v2/registry/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wolfplus2048/mcbeam-plus/mcb_handler"
+	"github.com/wolfplus2048/mcbeam-plus/message"
+	"github.com/wolfplus2048/mcbeam-plus/packet"
+	"github.com/wolfplus2048/mcbeam-plus/protos"
+	"github.com/wolfplus2048/mcbeam-plus/session"
+)
+
+type Table struct{}
+
+func (t *Table) Init()           {}
+func (t *Table) AfterInit()      {}
+func (t *Table) BeforeShutdown() {}
+func (t *Table) Shutdown()       {}
+
+func (t *Table) Kick(ctx context.Context, msg *proto_mcbeam.KickMsg) (*proto_mcbeam.KickAnswer, error) {
+	return &proto_mcbeam.KickAnswer{}, nil
+}
+
+type Channel struct{}
+
+func (c *Channel) Init()           {}
+func (c *Channel) AfterInit()      {}
+func (c *Channel) BeforeShutdown() {}
+func (c *Channel) Shutdown()       {}
+
+func (c *Channel) Send(ctx context.Context, msg *proto_mcbeam.PushMsg) (*proto_mcbeam.UsersAnswer, error) {
+	return &proto_mcbeam.UsersAnswer{}, nil
+}
+
+// register runs a server with the handler in the registry as the service
+// would, publishing its routes in the node metadata
+func register(t *testing.T, r registry.Registry, name string, handler interface{}) {
+	s := mcb_handler.NewMcbServer(mcb_handler.WithName(name))
+	require.NoError(t, s.Handle(handler))
+	md, err := s.Metadata()
+	require.NoError(t, err)
+	require.NoError(t, r.Register(&registry.Service{
+		Name:  name,
+		Nodes: []*registry.Node{{Id: name + "-1", Address: "127.0.0.1:0", Metadata: md}},
+	}))
+}
+
+func TestLoadClusterRoutes(t *testing.T) {
+	r := memory.NewRegistry()
+	register(t, r, "room", &Table{})
+	register(t, r, "chat", &Channel{})
+
+	// the gateway runs neither of the servers
+	g := newTestGateway(t, Registry(r))
+	require.NoError(t, g.loadRoutes())
+
+	dict := message.GetDictionary()
+	room, chat := dict["room.table.kick"], dict["chat.channel.send"]
+	assert.NotZero(t, room)
+	assert.NotZero(t, chat)
+	assert.NotEqual(t, room, chat)
+
+	s := g.schema()
+	require.NotNil(t, s)
+	assert.Equal(t, "proto.mcbeam.KickMsg", s.Routes["room.table.kick"].Request)
+	assert.Equal(t, "proto.mcbeam.UsersAnswer", s.Routes["chat.channel.send"].Response)
+
+	// clients get the schema of both servers on handshake
+	res := g.handshakeResponse(&session.HandshakeData{})
+	protos := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(res.Sys.Protos, &protos))
+	assert.Equal(t, s.Version, protos["version"])
+	assert.Equal(t, room, res.Sys.Dict["room.table.kick"])
+
+	// a server joining later does not move the codes clients already have
+	register(t, r, "area", &Channel{})
+	require.NoError(t, g.loadRoutes())
+	dict = message.GetDictionary()
+	assert.Equal(t, room, dict["room.table.kick"])
+	assert.Equal(t, chat, dict["chat.channel.send"])
+	assert.NotZero(t, dict["area.channel.send"])
+	assert.NotEqual(t, s.Version, g.schema().Version)
+}
+
+func TestConnectionDictionary(t *testing.T) {
+	require.NoError(t, message.AddRoutes("room.table.join"))
+	g := newTestGateway(t)
+	c := g.connect(t)
+	res := &packet.HandshakeResponse{}
+	require.NoError(t, json.Unmarshal(c.handshake(`{"sys":{"platform":"web"}}`).Data, res))
+	assert.Contains(t, res.Sys.Dict, "room.table.join")
+
+	// found by a refresh after the handshake of the client
+	require.NoError(t, message.AddRoutes("room.table.leave"))
+	a := onlyAgent(t, g)
+	tables := []struct {
+		route      string
+		compressed bool
+	}{
+		{"room.table.join", true},
+		{"room.table.leave", false},
+	}
+	for _, table := range tables {
+		require.NoError(t, a.Push(table.route, []byte("x")))
+		p, err := c.read(time.Second)
+		require.NoError(t, err)
+		assert.Equal(t, table.compressed, p.Data[0]&0x01 == 0x01, table.route)
+		m, err := message.Decode(p.Data)
+		require.NoError(t, err)
+		assert.Equal(t, table.route, m.Route)
+	}
+}
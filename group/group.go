@@ -0,0 +1,114 @@
+package group
+
+import (
+	"context"
+	"time"
+
+	"github.com/wolfplus2048/mcbeam-plus/constants"
+)
+
+// Service keeps the groups and their members, members are uids
+type Service interface {
+	// Create creates the group, it fails if the group exists
+	Create(ctx context.Context, name string) error
+	// CreateWithTTL creates a group deleted once ttl passed since its
+	// creation or its last RenewTTL
+	CreateWithTTL(ctx context.Context, name string, ttl time.Duration) error
+	// Delete deletes the group and all its members
+	Delete(ctx context.Context, name string) error
+	// AddMember adds the uid to the group
+	AddMember(ctx context.Context, name, uid string) error
+	// RemoveMember removes the uid from the group
+	RemoveMember(ctx context.Context, name, uid string) error
+	// RemoveAll removes all the members of the group, keeping the group
+	RemoveAll(ctx context.Context, name string) error
+	// ContainsMember tells if the uid is a member of the group
+	ContainsMember(ctx context.Context, name, uid string) (bool, error)
+	// Members returns the uids of the group members
+	Members(ctx context.Context, name string) ([]string, error)
+	// CountMembers returns the number of members of the group
+	CountMembers(ctx context.Context, name string) (int, error)
+	// RenewTTL restarts the ttl of a group created with one
+	RenewTTL(ctx context.Context, name string) error
+}
+
+// Groups pushes to the members of the groups of its service, all the
+// methods of the service can be called on it
+type Groups struct {
+	Service
+	pusher *Pusher
+}
+
+// New returns the groups of s, pushing through p
+func New(s Service, p *Pusher) *Groups {
+	return &Groups{
+		Service: s,
+		pusher:  p,
+	}
+}
+
+// Broadcast pushes the message to all the members of the group, it returns
+// the uids the push failed for
+func (g *Groups) Broadcast(ctx context.Context, name, route string, v interface{}) ([]string, error) {
+	uids, err := g.Members(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return g.push(ctx, route, v, uids)
+}
+
+// Multicast pushes the message to the uids that are members of the group,
+// the others are returned with the uids the push failed for
+func (g *Groups) Multicast(ctx context.Context, name string, uids []string, route string, v interface{}) ([]string, error) {
+	var members, failed []string
+	for _, uid := range uids {
+		ok, err := g.ContainsMember(ctx, name, uid)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			members = append(members, uid)
+		} else {
+			failed = append(failed, uid)
+		}
+	}
+	res, err := g.push(ctx, route, v, members)
+	failed = append(failed, res...)
+	if err == nil && len(failed) > 0 {
+		err = constants.ErrPushingToUsers
+	}
+	return failed, err
+}
+
+func (g *Groups) push(ctx context.Context, route string, v interface{}, uids []string) ([]string, error) {
+	if len(uids) == 0 {
+		return nil, nil
+	}
+	failed := g.pusher.Push(ctx, route, v, uids)
+	if len(failed) > 0 {
+		return failed, constants.ErrPushingToUsers
+	}
+	return nil, nil
+}
+
+// Init starts the service when it has to run in the background
+func (g *Groups) Init() error {
+	if s, ok := g.Service.(interface{ Init() error }); ok {
+		return s.Init()
+	}
+	return nil
+}
+
+// AfterInit is called after the module is initialized
+func (g *Groups) AfterInit() {}
+
+// BeforeShutdown is called before the module is shut down
+func (g *Groups) BeforeShutdown() {}
+
+// Shutdown stops the service when it runs in the background
+func (g *Groups) Shutdown() error {
+	if s, ok := g.Service.(interface{ Shutdown() error }); ok {
+		return s.Shutdown()
+	}
+	return nil
+}
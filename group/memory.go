@@ -0,0 +1,229 @@
+package group
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/wolfplus2048/mcbeam-plus/constants"
+)
+
+// DefaultCleanupInterval is how often the memory service deletes the
+// groups whose ttl passed
+const DefaultCleanupInterval = time.Minute
+
+type memoryGroup struct {
+	members     map[string]struct{}
+	ttl         time.Duration
+	lastRefresh time.Time
+}
+
+func (g *memoryGroup) expired(now time.Time) bool {
+	return g.ttl > 0 && now.Sub(g.lastRefresh) >= g.ttl
+}
+
+// MemoryService keeps the groups in memory, they are only seen by the
+// server that created them
+type MemoryService struct {
+	mutex    sync.Mutex
+	groups   map[string]*memoryGroup
+	interval time.Duration
+	done     chan struct{}
+	now      func() time.Time
+}
+
+// NewMemoryService returns a memory group service, the groups whose ttl
+// passed are deleted every interval, DefaultCleanupInterval if it is zero
+func NewMemoryService(interval time.Duration) *MemoryService {
+	if interval <= 0 {
+		interval = DefaultCleanupInterval
+	}
+	return &MemoryService{
+		groups:   make(map[string]*memoryGroup),
+		interval: interval,
+		now:      time.Now,
+	}
+}
+
+// Init starts deleting the expired groups
+func (m *MemoryService) Init() error {
+	done := make(chan struct{})
+	m.done = done
+	go func() {
+		ticker := time.NewTicker(m.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				m.cleanup()
+			case <-done:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// Shutdown stops deleting the expired groups
+func (m *MemoryService) Shutdown() error {
+	if m.done != nil {
+		close(m.done)
+		m.done = nil
+	}
+	return nil
+}
+
+func (m *MemoryService) cleanup() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	now := m.now()
+	for name, g := range m.groups {
+		if g.expired(now) {
+			delete(m.groups, name)
+		}
+	}
+}
+
+// get returns the group, expired groups are deleted rather than returned.
+// The caller holds the write lock.
+func (m *MemoryService) get(name string) (*memoryGroup, error) {
+	g, ok := m.groups[name]
+	if !ok {
+		return nil, constants.ErrGroupNotFound
+	}
+	if g.expired(m.now()) {
+		delete(m.groups, name)
+		return nil, constants.ErrGroupNotFound
+	}
+	return g, nil
+}
+
+// Create creates the group, it fails if the group exists
+func (m *MemoryService) Create(ctx context.Context, name string) error {
+	return m.CreateWithTTL(ctx, name, 0)
+}
+
+// CreateWithTTL creates a group deleted once ttl passed since its creation
+// or its last RenewTTL, a zero ttl never expires
+func (m *MemoryService) CreateWithTTL(ctx context.Context, name string, ttl time.Duration) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if _, err := m.get(name); err == nil {
+		return constants.ErrGroupAlreadyExists
+	}
+	m.groups[name] = &memoryGroup{
+		members:     make(map[string]struct{}),
+		ttl:         ttl,
+		lastRefresh: m.now(),
+	}
+	return nil
+}
+
+// Delete deletes the group and all its members
+func (m *MemoryService) Delete(ctx context.Context, name string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if _, err := m.get(name); err != nil {
+		return err
+	}
+	delete(m.groups, name)
+	return nil
+}
+
+// AddMember adds the uid to the group
+func (m *MemoryService) AddMember(ctx context.Context, name, uid string) error {
+	if uid == "" {
+		return constants.ErrEmptyUID
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	g, err := m.get(name)
+	if err != nil {
+		return err
+	}
+	if _, ok := g.members[uid]; ok {
+		return constants.ErrMemberAlreadyExists
+	}
+	g.members[uid] = struct{}{}
+	return nil
+}
+
+// RemoveMember removes the uid from the group
+func (m *MemoryService) RemoveMember(ctx context.Context, name, uid string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	g, err := m.get(name)
+	if err != nil {
+		return err
+	}
+	if _, ok := g.members[uid]; !ok {
+		return constants.ErrMemberNotFound
+	}
+	delete(g.members, uid)
+	return nil
+}
+
+// RemoveAll removes all the members of the group, keeping the group
+func (m *MemoryService) RemoveAll(ctx context.Context, name string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	g, err := m.get(name)
+	if err != nil {
+		return err
+	}
+	g.members = make(map[string]struct{})
+	return nil
+}
+
+// ContainsMember tells if the uid is a member of the group
+func (m *MemoryService) ContainsMember(ctx context.Context, name, uid string) (bool, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	g, err := m.get(name)
+	if err != nil {
+		return false, err
+	}
+	_, ok := g.members[uid]
+	return ok, nil
+}
+
+// Members returns the uids of the group members
+func (m *MemoryService) Members(ctx context.Context, name string) ([]string, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	g, err := m.get(name)
+	if err != nil {
+		return nil, err
+	}
+	uids := make([]string, 0, len(g.members))
+	for uid := range g.members {
+		uids = append(uids, uid)
+	}
+	return uids, nil
+}
+
+// CountMembers returns the number of members of the group
+func (m *MemoryService) CountMembers(ctx context.Context, name string) (int, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	g, err := m.get(name)
+	if err != nil {
+		return 0, err
+	}
+	return len(g.members), nil
+}
+
+// RenewTTL restarts the ttl of a group created with one
+func (m *MemoryService) RenewTTL(ctx context.Context, name string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	g, err := m.get(name)
+	if err != nil {
+		return err
+	}
+	if g.ttl == 0 {
+		return constants.ErrMemoryTTLNotFound
+	}
+	g.lastRefresh = m.now()
+	return nil
+}
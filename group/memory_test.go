@@ -0,0 +1,75 @@
+package group
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wolfplus2048/mcbeam-plus/constants"
+)
+
+func TestMemoryServiceMembers(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemoryService(0)
+
+	assert.Equal(t, constants.ErrGroupNotFound, m.AddMember(ctx, "room", "u1"))
+	assert.NoError(t, m.Create(ctx, "room"))
+	assert.Equal(t, constants.ErrGroupAlreadyExists, m.Create(ctx, "room"))
+
+	assert.NoError(t, m.AddMember(ctx, "room", "u1"))
+	assert.NoError(t, m.AddMember(ctx, "room", "u2"))
+	assert.Equal(t, constants.ErrMemberAlreadyExists, m.AddMember(ctx, "room", "u1"))
+	assert.Equal(t, constants.ErrEmptyUID, m.AddMember(ctx, "room", ""))
+
+	uids, err := m.Members(ctx, "room")
+	assert.NoError(t, err)
+	sort.Strings(uids)
+	assert.Equal(t, []string{"u1", "u2"}, uids)
+
+	ok, err := m.ContainsMember(ctx, "room", "u2")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	assert.NoError(t, m.RemoveMember(ctx, "room", "u2"))
+	assert.Equal(t, constants.ErrMemberNotFound, m.RemoveMember(ctx, "room", "u2"))
+	count, err := m.CountMembers(ctx, "room")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	assert.NoError(t, m.RemoveAll(ctx, "room"))
+	count, err = m.CountMembers(ctx, "room")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+
+	assert.NoError(t, m.Delete(ctx, "room"))
+	assert.Equal(t, constants.ErrGroupNotFound, m.Delete(ctx, "room"))
+}
+
+func TestMemoryServiceTTL(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	m := NewMemoryService(0)
+	m.now = func() time.Time { return now }
+
+	assert.NoError(t, m.Create(ctx, "forever"))
+	assert.Equal(t, constants.ErrMemoryTTLNotFound, m.RenewTTL(ctx, "forever"))
+
+	assert.NoError(t, m.CreateWithTTL(ctx, "room", time.Minute))
+	now = now.Add(50 * time.Second)
+	assert.NoError(t, m.RenewTTL(ctx, "room"))
+	now = now.Add(50 * time.Second)
+	_, err := m.Members(ctx, "room")
+	assert.NoError(t, err)
+
+	now = now.Add(time.Minute)
+	_, err = m.Members(ctx, "room")
+	assert.Equal(t, constants.ErrGroupNotFound, err)
+	assert.NoError(t, m.CreateWithTTL(ctx, "room", time.Minute))
+
+	now = now.Add(time.Minute)
+	m.cleanup()
+	assert.Len(t, m.groups, 1)
+	assert.Contains(t, m.groups, "forever")
+}
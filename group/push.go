@@ -0,0 +1,64 @@
+package group
+
+import (
+	"context"
+
+	"github.com/micro/go-micro/v2/client"
+	"github.com/micro/go-micro/v2/logger"
+	"github.com/wolfplus2048/mcbeam-plus/constants"
+	"github.com/wolfplus2048/mcbeam-plus/fanout"
+	"github.com/wolfplus2048/mcbeam-plus/modules"
+	"github.com/wolfplus2048/mcbeam-plus/serialize/protobuf"
+	"github.com/wolfplus2048/mcbeam-plus/session"
+	"github.com/wolfplus2048/mcbeam-plus/util"
+)
+
+// Pusher pushes to the sessions of this frontend directly, the users bound
+// on other frontends are found through the binding storage
+type Pusher struct {
+	bindingStorage *modules.BindingStorage
+	client         client.Client
+}
+
+// NewPusher returns a pusher sending the remote pushes with c, bs can be
+// nil when all the members are on this frontend
+func NewPusher(bs *modules.BindingStorage, c client.Client) *Pusher {
+	return &Pusher{
+		bindingStorage: bs,
+		client:         c,
+	}
+}
+
+// Push pushes the message to the users, it returns the uids the push
+// failed for
+func (p *Pusher) Push(ctx context.Context, route string, v interface{}, uids []string) []string {
+	var failed, remote []string
+	for _, uid := range uids {
+		s := session.GetSessionByUID(uid)
+		if s == nil {
+			remote = append(remote, uid)
+			continue
+		}
+		if err := s.Push(route, v); err != nil {
+			logger.Errorf("Failed to push to uid %s: %s", uid, err.Error())
+			failed = append(failed, uid)
+		}
+	}
+	if len(remote) == 0 {
+		return failed
+	}
+	if p.bindingStorage == nil {
+		logger.Errorf("Failed to push to uids %v: %s", remote, constants.ErrNoBindingStorageModule.Error())
+		return append(failed, remote...)
+	}
+	data, err := util.SerializeOrRaw(protobuf.NewSerializer(), v)
+	if err != nil {
+		logger.Errorf("Failed to serialize push to route %s: %s", route, err.Error())
+		return append(failed, remote...)
+	}
+	res, err := fanout.ToUsers(p.bindingStorage, nil, "", remote, fanout.PushToUsers(ctx, p.client, route, data))
+	if err != nil {
+		logger.Errorf("Failed to push to uids %v: %s", remote, err.Error())
+	}
+	return append(failed, res...)
+}
@@ -0,0 +1,306 @@
+package group
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/micro/go-micro/v2/logger"
+	"github.com/micro/go-micro/v2/store"
+	"github.com/wolfplus2048/mcbeam-plus/constants"
+)
+
+const (
+	storeGroupPrefix  = "mcbeam/groups/"
+	storeMemberPrefix = "mcbeam/group_members/"
+)
+
+// DefaultMemberLease is how long a member stays in its group once the
+// server that added it stopped renewing it
+const DefaultMemberLease = 30 * time.Second
+
+type storeGroup struct {
+	TTL time.Duration `json:"ttl"`
+}
+
+// StoreService keeps the groups in a store shared by the cluster, so any
+// server sees the same groups. Members are written with a lease the server
+// that added them renews, they are dropped once it dies. The store has no
+// transactions, two servers adding the same member at once can both
+// succeed.
+type StoreService struct {
+	store    store.Store
+	lease    time.Duration
+	mutex    sync.Mutex
+	renewing map[string]string
+	done     chan struct{}
+}
+
+// NewStoreService returns a group service writing to s, members are leased
+// for lease, DefaultMemberLease if it is zero
+func NewStoreService(s store.Store, lease time.Duration) *StoreService {
+	if lease <= 0 {
+		lease = DefaultMemberLease
+	}
+	return &StoreService{
+		store:    s,
+		lease:    lease,
+		renewing: make(map[string]string),
+	}
+}
+
+// Init starts renewing the leases of the members added by this server
+func (m *StoreService) Init() error {
+	done := make(chan struct{})
+	m.done = done
+	go func() {
+		ticker := time.NewTicker(m.lease / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				m.renew()
+			case <-done:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// Shutdown stops renewing the leases, the members expire with them
+func (m *StoreService) Shutdown() error {
+	if m.done != nil {
+		close(m.done)
+		m.done = nil
+	}
+	return nil
+}
+
+// renew rewrites the members added by this server, unless they or their
+// group were removed since
+func (m *StoreService) renew() {
+	m.mutex.Lock()
+	renewing := make(map[string]string, len(m.renewing))
+	for key, name := range m.renewing {
+		renewing[key] = name
+	}
+	m.mutex.Unlock()
+
+	groups := make(map[string]bool)
+	for key, name := range renewing {
+		exists, ok := groups[name]
+		if !ok {
+			_, err := m.group(name)
+			exists = err == nil
+			groups[name] = exists
+		}
+		if exists {
+			exists = m.exists(key)
+		}
+		if !exists {
+			m.forget(key)
+			continue
+		}
+		if err := m.store.Write(&store.Record{Key: key, Expiry: m.lease}); err != nil {
+			logger.Errorf("Failed to renew group member %s: %s", key, err.Error())
+		}
+	}
+}
+
+func (m *StoreService) forget(keys ...string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for _, key := range keys {
+		delete(m.renewing, key)
+	}
+}
+
+func (m *StoreService) exists(key string) bool {
+	recs, err := m.store.Read(key)
+	return err == nil && len(recs) > 0
+}
+
+func (m *StoreService) group(name string) (*storeGroup, error) {
+	recs, err := m.store.Read(groupKey(name))
+	if err == store.ErrNotFound || (err == nil && len(recs) == 0) {
+		return nil, constants.ErrGroupNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	g := &storeGroup{}
+	if err := json.Unmarshal(recs[0].Value, g); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+func (m *StoreService) writeGroup(name string, g *storeGroup) error {
+	b, err := json.Marshal(g)
+	if err != nil {
+		return err
+	}
+	return m.store.Write(&store.Record{Key: groupKey(name), Value: b, Expiry: g.TTL})
+}
+
+func (m *StoreService) memberKeys(name string) ([]string, error) {
+	return m.store.List(store.ListPrefix(memberPrefix(name)))
+}
+
+func (m *StoreService) deleteMembers(name string) error {
+	keys, err := m.memberKeys(name)
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if err := m.store.Delete(key); err != nil && err != store.ErrNotFound {
+			return err
+		}
+	}
+	m.forget(keys...)
+	return nil
+}
+
+// Create creates the group, it fails if the group exists
+func (m *StoreService) Create(ctx context.Context, name string) error {
+	return m.CreateWithTTL(ctx, name, 0)
+}
+
+// CreateWithTTL creates a group deleted once ttl passed since its creation
+// or its last RenewTTL, a zero ttl never expires
+func (m *StoreService) CreateWithTTL(ctx context.Context, name string, ttl time.Duration) error {
+	_, err := m.group(name)
+	if err == nil {
+		return constants.ErrGroupAlreadyExists
+	}
+	if err != constants.ErrGroupNotFound {
+		return err
+	}
+	// members of an expired group with the same name can be left
+	if err := m.deleteMembers(name); err != nil {
+		return err
+	}
+	return m.writeGroup(name, &storeGroup{TTL: ttl})
+}
+
+// Delete deletes the group and all its members
+func (m *StoreService) Delete(ctx context.Context, name string) error {
+	if _, err := m.group(name); err != nil {
+		return err
+	}
+	if err := m.store.Delete(groupKey(name)); err != nil {
+		return err
+	}
+	return m.deleteMembers(name)
+}
+
+// AddMember adds the uid to the group, leased by this server
+func (m *StoreService) AddMember(ctx context.Context, name, uid string) error {
+	if uid == "" {
+		return constants.ErrEmptyUID
+	}
+	if _, err := m.group(name); err != nil {
+		return err
+	}
+	key := memberKey(name, uid)
+	if m.exists(key) {
+		return constants.ErrMemberAlreadyExists
+	}
+	if err := m.store.Write(&store.Record{Key: key, Expiry: m.lease}); err != nil {
+		return err
+	}
+	m.mutex.Lock()
+	m.renewing[key] = name
+	m.mutex.Unlock()
+	return nil
+}
+
+// RemoveMember removes the uid from the group
+func (m *StoreService) RemoveMember(ctx context.Context, name, uid string) error {
+	if _, err := m.group(name); err != nil {
+		return err
+	}
+	key := memberKey(name, uid)
+	if !m.exists(key) {
+		return constants.ErrMemberNotFound
+	}
+	if err := m.store.Delete(key); err != nil {
+		return err
+	}
+	m.forget(key)
+	return nil
+}
+
+// RemoveAll removes all the members of the group, keeping the group
+func (m *StoreService) RemoveAll(ctx context.Context, name string) error {
+	if _, err := m.group(name); err != nil {
+		return err
+	}
+	return m.deleteMembers(name)
+}
+
+// ContainsMember tells if the uid is a member of the group
+func (m *StoreService) ContainsMember(ctx context.Context, name, uid string) (bool, error) {
+	if _, err := m.group(name); err != nil {
+		return false, err
+	}
+	return m.exists(memberKey(name, uid)), nil
+}
+
+// Members returns the uids of the group members
+func (m *StoreService) Members(ctx context.Context, name string) ([]string, error) {
+	if _, err := m.group(name); err != nil {
+		return nil, err
+	}
+	keys, err := m.memberKeys(name)
+	if err != nil {
+		return nil, err
+	}
+	prefix := memberPrefix(name)
+	uids := make([]string, 0, len(keys))
+	for _, key := range keys {
+		uid, err := url.PathUnescape(strings.TrimPrefix(key, prefix))
+		if err != nil {
+			logger.Errorf("Failed to decode group member %s: %s", key, err.Error())
+			continue
+		}
+		uids = append(uids, uid)
+	}
+	return uids, nil
+}
+
+// CountMembers returns the number of members of the group
+func (m *StoreService) CountMembers(ctx context.Context, name string) (int, error) {
+	uids, err := m.Members(ctx, name)
+	return len(uids), err
+}
+
+// RenewTTL restarts the ttl of a group created with one
+func (m *StoreService) RenewTTL(ctx context.Context, name string) error {
+	g, err := m.group(name)
+	if err != nil {
+		return err
+	}
+	if g.TTL == 0 {
+		return constants.ErrEtcdLeaseNotFound
+	}
+	return m.writeGroup(name, g)
+}
+
+// groupKey and memberKey escape the names so that no group is a prefix of
+// another one
+func groupKey(name string) string {
+	return storeGroupPrefix + url.PathEscape(name)
+}
+
+func memberPrefix(name string) string {
+	return storeMemberPrefix + url.PathEscape(name) + "/"
+}
+
+func memberKey(name, uid string) string {
+	return memberPrefix(name) + url.PathEscape(uid)
+}
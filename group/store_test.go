@@ -0,0 +1,99 @@
+package group
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v2/store/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/wolfplus2048/mcbeam-plus/constants"
+)
+
+func TestStoreServiceSharedGroups(t *testing.T) {
+	ctx := context.Background()
+	s := memory.NewStore()
+	a := NewStoreService(s, 0)
+	b := NewStoreService(s, 0)
+
+	assert.NoError(t, a.Create(ctx, "room"))
+	assert.Equal(t, constants.ErrGroupAlreadyExists, b.Create(ctx, "room"))
+	assert.NoError(t, b.Create(ctx, "room/2"))
+
+	assert.NoError(t, a.AddMember(ctx, "room", "u1"))
+	assert.NoError(t, b.AddMember(ctx, "room", "u2"))
+	assert.Equal(t, constants.ErrMemberAlreadyExists, b.AddMember(ctx, "room", "u1"))
+	assert.NoError(t, b.AddMember(ctx, "room/2", "u3"))
+
+	uids, err := b.Members(ctx, "room")
+	assert.NoError(t, err)
+	sort.Strings(uids)
+	assert.Equal(t, []string{"u1", "u2"}, uids)
+
+	ok, err := a.ContainsMember(ctx, "room", "u2")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	assert.NoError(t, b.RemoveMember(ctx, "room", "u1"))
+	assert.Equal(t, constants.ErrMemberNotFound, a.RemoveMember(ctx, "room", "u1"))
+	count, err := a.CountMembers(ctx, "room")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	assert.NoError(t, a.Delete(ctx, "room"))
+	_, err = b.Members(ctx, "room")
+	assert.Equal(t, constants.ErrGroupNotFound, err)
+	count, err = b.CountMembers(ctx, "room/2")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
+
+func TestStoreServiceMemberLease(t *testing.T) {
+	ctx := context.Background()
+	s := memory.NewStore()
+	alive := NewStoreService(s, 90*time.Millisecond)
+	dead := NewStoreService(s, 90*time.Millisecond)
+	assert.NoError(t, alive.Init())
+	defer alive.Shutdown()
+
+	assert.NoError(t, alive.Create(ctx, "room"))
+	assert.NoError(t, alive.AddMember(ctx, "room", "u1"))
+	assert.NoError(t, dead.AddMember(ctx, "room", "u2"))
+
+	time.Sleep(200 * time.Millisecond)
+	uids, err := dead.Members(ctx, "room")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"u1"}, uids)
+
+	assert.NoError(t, dead.RemoveMember(ctx, "room", "u1"))
+	time.Sleep(100 * time.Millisecond)
+	ok, err := alive.ContainsMember(ctx, "room", "u1")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestStoreServiceTTL(t *testing.T) {
+	ctx := context.Background()
+	m := NewStoreService(memory.NewStore(), 0)
+
+	assert.NoError(t, m.Create(ctx, "forever"))
+	assert.Equal(t, constants.ErrEtcdLeaseNotFound, m.RenewTTL(ctx, "forever"))
+
+	assert.NoError(t, m.CreateWithTTL(ctx, "room", 60*time.Millisecond))
+	assert.NoError(t, m.AddMember(ctx, "room", "u1"))
+	time.Sleep(40 * time.Millisecond)
+	assert.NoError(t, m.RenewTTL(ctx, "room"))
+	time.Sleep(40 * time.Millisecond)
+	_, err := m.Members(ctx, "room")
+	assert.NoError(t, err)
+
+	time.Sleep(40 * time.Millisecond)
+	_, err = m.Members(ctx, "room")
+	assert.Equal(t, constants.ErrGroupNotFound, err)
+
+	assert.NoError(t, m.Create(ctx, "room"))
+	uids, err := m.Members(ctx, "room")
+	assert.NoError(t, err)
+	assert.Empty(t, uids)
+}
@@ -0,0 +1,29 @@
+package mcbeam
+
+import (
+	"context"
+
+	"github.com/micro/go-micro/v2/logger"
+	"github.com/wolfplus2048/mcbeam-plus/constants"
+	"github.com/wolfplus2048/mcbeam-plus/fanout"
+)
+
+// SendKickToUsers kicks the users, sending one rpc to each frontend they
+// are bound on. The reason is sent to the clients in the kick packet and
+// is the close reason of the sessions. It returns the uids the kick failed
+// for. Without a Store the users are looked for on every frontend of
+// frontendType.
+func SendKickToUsers(ctx context.Context, uids []string, reason string, frontendType string) ([]string, error) {
+	if app == nil {
+		return uids, constants.ErrNoBindingStorageModule
+	}
+	logger.Debugf("Type=SendKickToUsers, Reason=%s, UIDs=%v", reason, uids)
+	failed, err := sendToUsers(uids, frontendType, fanout.KickUsers(ctx, app.Client(), reason))
+	if err != nil {
+		return failed, err
+	}
+	if len(failed) > 0 {
+		return failed, constants.ErrKickingUsers
+	}
+	return nil, nil
+}
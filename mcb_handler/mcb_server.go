@@ -2,6 +2,7 @@ package mcb_handler
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"github.com/golang/protobuf/proto"
 	e "github.com/micro/go-micro/v2/errors"
@@ -12,8 +13,11 @@ import (
 	"github.com/wolfplus2048/mcbeam-plus/message"
 	"github.com/wolfplus2048/mcbeam-plus/protos"
 	"github.com/wolfplus2048/mcbeam-plus/route"
+	"github.com/wolfplus2048/mcbeam-plus/schema"
 	"github.com/wolfplus2048/mcbeam-plus/util"
 	"reflect"
+	"runtime/debug"
+	"sort"
 	"strings"
 )
 
@@ -51,6 +55,64 @@ func (m *McbServer) Init(opts ...Option) {
 	for _, o := range opts {
 		o(&m.opts)
 	}
+	// components may have been registered before the server knew its name
+	keys := make([]string, 0, len(m.handlers))
+	for key := range m.handlers {
+		keys = append(keys, key)
+	}
+	if err := m.exportRoutes(keys...); err != nil {
+		logger.Errorf("failed to build route schema: %s", err.Error())
+	}
+}
+
+// exportRoutes adds the client routes of the handlers to the protobuf
+// schema, gateways get them through Metadata from the registry
+func (m *McbServer) exportRoutes(keys ...string) error {
+	if m.opts.name == "" {
+		return nil
+	}
+	for _, key := range keys {
+		if h := m.handlers[key]; !h.IsRawArg {
+			schema.Register(fmt.Sprintf("%s.%s", m.opts.name, key), h.Type, h.ReturnType())
+		}
+	}
+	return nil
+}
+
+// Routes returns the sorted client routes of the handlers
+func (m *McbServer) Routes() []string {
+	if m.opts.name == "" {
+		return nil
+	}
+	routes := make([]string, 0, len(m.handlers))
+	for key := range m.handlers {
+		routes = append(routes, fmt.Sprintf("%s.%s", m.opts.name, key))
+	}
+	sort.Strings(routes)
+	return routes
+}
+
+// Metadata returns the registry node metadata publishing the routes and
+// their schema, gateways build the dictionary and schema they send to
+// clients from the metadata of all the servers of the cluster
+func (m *McbServer) Metadata() (map[string]string, error) {
+	routes := m.Routes()
+	rs, err := json.Marshal(routes)
+	if err != nil {
+		return nil, err
+	}
+	s, err := schema.Of(routes...)
+	if err != nil {
+		return nil, err
+	}
+	sc, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{
+		constants.RoutesMetadataKey: string(rs),
+		constants.SchemaMetadataKey: string(sc),
+	}, nil
 }
 
 func (m *McbServer) Handle(handler interface{}, opt ...component.HandlerOption) error {
@@ -93,12 +155,14 @@ func (m *McbServer) Handle(handler interface{}, opt ...component.HandlerOption)
 		handlers[i].Receiver = receiver
 	}
 
+	keys := make([]string, 0, len(handlers))
 	for n, handler := range handlers {
 		key := fmt.Sprintf("%s.%s", name, n)
 		m.handlers[key] = handler
-		logger.Infof("registered component %s, isRawArg: %s", key, m.handlers[key].IsRawArg)
+		keys = append(keys, key)
+		logger.Infof("registered component %s, isRawArg: %t", key, m.handlers[key].IsRawArg)
 	}
-	return nil
+	return m.exportRoutes(keys...)
 }
 
 func (m *McbServer) Call(ctx context.Context, req *proto_mcbeam.Request, res *proto_mcbeam.Response) error {
@@ -132,25 +196,18 @@ func (m *McbServer) handleRPCSys(ctx context.Context, req *proto_mcbeam.Request,
 	} else if err != nil {
 		logger.Warnf("invalid message type, error: %s", err.Error())
 	}
-	args := []reflect.Value{handler.Receiver, reflect.ValueOf(ctx)}
 	arg, err := unmarshalHandlerArg(handler, m.opts.serializer, req.GetMsg().GetData())
 	if err != nil {
 		return e.BadRequest(m.opts.name, "invalid arg:%s", err.Error())
 	}
-	if arg != nil {
-		args = append(args, reflect.ValueOf(arg))
-	}
-	if handler.IsRawArg && handler.Method.Type.NumIn() == 4 {
-		args = append(args, reflect.ValueOf(rt.Method))
-	}
-	resp, err := util.Pcall(handler.Method, args)
+	resp, err := m.call(ctx, handler, &Request{Route: rt, Type: msgType, Arg: arg})
 
 	if msgType == message.Notify {
 		resp = []byte("ack")
 	}
 
 	if err != nil {
-		return e.BadRequest(m.opts.name, err.Error())
+		return handlerError(m.opts.name, err)
 	}
 	data, _ := serializeReturn(m.opts.serializer, resp)
 	res.Data = data
@@ -173,33 +230,73 @@ func (m *McbServer) handleRPCUser(ctx context.Context, req *proto_mcbeam.Request
 	} else if err != nil {
 		logger.Warnf("invalid message type, error: %s", err.Error())
 	}
-	a, err := agent.NewRemote(req.GetSession(), req.Msg.Reply, m.opts.rpcClient, req.FrontendID, m.opts.serializer)
+	a, err := agent.NewRemote(ctx, req.GetSession(), req.Msg.Reply, m.opts.rpcClient, req.FrontendID, m.opts.serializer)
+	if err != nil {
+		return e.InternalServerError(m.opts.name, "%s", err.Error())
+	}
 
 	ctx = context.WithValue(ctx, constants.SessionCtxKey, a.Session)
-	args := []reflect.Value{handler.Receiver, reflect.ValueOf(ctx)}
+	ctx = context.WithValue(ctx, constants.MessageIDCtxKey, uint(req.GetMsg().GetId()))
 	arg, err := unmarshalHandlerArg(handler, m.opts.serializer, req.GetMsg().GetData())
 	if err != nil {
 		return e.BadRequest(m.opts.name, "invalid arg:%s", err.Error())
 	}
-	if arg != nil {
-		args = append(args, reflect.ValueOf(arg))
+	resp, err := m.call(ctx, handler, &Request{Route: rt, Type: msgType, Arg: arg, Session: a.Session})
+	// the pushes of the handler reach the client before its response
+	a.Flush()
+	if err == constants.ErrResponseDeferred {
+		// the handler responds later through Session.ResponseMID
+		res.Deferred = true
+		return nil
 	}
-	if handler.IsRawArg && handler.Method.Type.NumIn() == 4 {
-		args = append(args, reflect.ValueOf(rt.Method))
-	}
-	resp, err := util.Pcall(handler.Method, args)
 
 	if msgType == message.Notify {
 		resp = []byte("ack")
 	}
 
 	if err != nil {
-		return e.BadRequest(m.opts.name, err.Error())
+		return handlerError(m.opts.name, err)
 	}
 	data, _ := serializeReturn(m.opts.serializer, resp)
 	res.Data = data
 	return nil
 }
+
+// call calls the handler method through the handler wrappers, a panic
+// of a wrapper fails the call like one of the method does
+func (m *McbServer) call(ctx context.Context, handler *Handler, req *Request) (resp interface{}, err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			logger.Errorf("panic - handler wrapper: %s: %v", req.Route.Short(), rec)
+			logger.Debugf("%s", debug.Stack())
+			resp, err = nil, fmt.Errorf("rpc call internal error - %s: %v", req.Route.Short(), rec)
+		}
+	}()
+	fn := func(ctx context.Context, req *Request) (interface{}, error) {
+		args := []reflect.Value{handler.Receiver, reflect.ValueOf(ctx)}
+		if req.Arg != nil {
+			args = append(args, reflect.ValueOf(req.Arg))
+		}
+		if handler.IsRawArg && handler.Method.Type.NumIn() == 4 {
+			args = append(args, reflect.ValueOf(req.Route.Method))
+		}
+		return util.Pcall(handler.Method, args)
+	}
+	for i := len(m.opts.HdlrWrappers); i > 0; i-- {
+		fn = m.opts.HdlrWrappers[i-1](fn)
+	}
+	return fn(ctx, req)
+}
+
+// handlerError keeps the rpc errors the handlers or their wrappers return,
+// like an unauthorized one, the others are bad requests
+func handlerError(name string, err error) error {
+	if merr, ok := err.(*e.Error); ok {
+		return merr
+	}
+	return e.BadRequest(name, err.Error())
+}
+
 func (h *Handler) ValidateMessageType(msgType message.Type) (exitOnError bool, err error) {
 	if h.MessageType != msgType {
 		switch msgType {
@@ -213,3 +310,11 @@ func (h *Handler) ValidateMessageType(msgType message.Type) (exitOnError bool, e
 	}
 	return
 }
+
+// ReturnType returns the type of the handler response, nil for notify handlers
+func (h *Handler) ReturnType() reflect.Type {
+	if h.Method.Type.NumOut() == 0 {
+		return nil
+	}
+	return h.Method.Type.Out(0)
+}
@@ -0,0 +1,127 @@
+package mcb_handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	e "github.com/micro/go-micro/v2/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wolfplus2048/mcbeam-plus/protos"
+	"github.com/wolfplus2048/mcbeam-plus/serialize/protobuf"
+)
+
+// Room is the component called by the tests
+type Room struct {
+	calls []string
+}
+
+func (r *Room) Kick(ctx context.Context, in *proto_mcbeam.KickMsg) (*proto_mcbeam.KickAnswer, error) {
+	r.calls = append(r.calls, in.GetUserId())
+	return &proto_mcbeam.KickAnswer{Kicked: true}, nil
+}
+
+// newRoomServer returns a server handling Room with the wrappers
+func newRoomServer(t *testing.T, w ...HandlerWrapper) (*McbServer, *Room) {
+	m := NewMcbServer(Serializer(protobuf.NewSerializer()), HandlerWrappers(w...))
+	r := &Room{}
+	require.NoError(t, m.Handle(r))
+	return m, r
+}
+
+// kick calls Room.Kick for uid as a client message
+func kick(t *testing.T, m *McbServer, uid string) (*proto_mcbeam.KickAnswer, error) {
+	data, err := m.opts.serializer.Marshal(&proto_mcbeam.KickMsg{UserId: uid})
+	require.NoError(t, err)
+	req := &proto_mcbeam.Request{
+		Type:       proto_mcbeam.RPCType_User,
+		Session:    &proto_mcbeam.Session{Id: 1, Uid: uid},
+		FrontendID: "gate-1",
+		Msg:        &proto_mcbeam.Msg{Id: 1, Route: "game.room.kick", Data: data, Type: proto_mcbeam.MsgType_MsgRequest},
+	}
+	res := &proto_mcbeam.Response{}
+	if err := m.Call(context.Background(), req, res); err != nil {
+		return nil, err
+	}
+	rsp := &proto_mcbeam.KickAnswer{}
+	require.NoError(t, m.opts.serializer.Unmarshal(res.GetData(), rsp))
+	return rsp, nil
+}
+
+// trace returns a wrapper recording when the call enters and leaves it
+func trace(name string, order *[]string) HandlerWrapper {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, req *Request) (interface{}, error) {
+			*order = append(*order, name+" in")
+			defer func() { *order = append(*order, name+" out") }()
+			return next(ctx, req)
+		}
+	}
+}
+
+func TestHandlerWrapperOrder(t *testing.T) {
+	var order []string
+	m, _ := newRoomServer(t, trace("first", &order))
+	m.Init(WrapHandler(trace("second", &order)))
+
+	_, err := kick(t, m, "u1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"first in", "second in", "second out", "first out"}, order)
+}
+
+func TestHandlerWrapperShortCircuit(t *testing.T) {
+	tables := []struct {
+		name string
+		err  error
+		code int32
+	}{
+		{"rpc error", e.Unauthorized("game", "no token"), http.StatusUnauthorized},
+		{"plain error", errors.New("no token"), http.StatusBadRequest},
+	}
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			m, r := newRoomServer(t, func(next HandlerFunc) HandlerFunc {
+				return func(ctx context.Context, req *Request) (interface{}, error) {
+					return nil, table.err
+				}
+			})
+			_, err := kick(t, m, "u1")
+			assert.Equal(t, table.code, e.Parse(err.Error()).Code)
+			assert.Empty(t, r.calls)
+		})
+	}
+}
+
+func TestHandlerWrapperRequest(t *testing.T) {
+	m, r := newRoomServer(t, func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, req *Request) (interface{}, error) {
+			assert.Equal(t, "room.kick", req.Route.Short())
+			assert.Equal(t, "u1", req.Arg.(*proto_mcbeam.KickMsg).GetUserId())
+			assert.Equal(t, "u1", req.Session.UID())
+			resp, err := next(ctx, req)
+			if err != nil {
+				return nil, err
+			}
+			// the wrapper has the last word on the result
+			resp.(*proto_mcbeam.KickAnswer).Kicked = false
+			return resp, nil
+		}
+	})
+	rsp, err := kick(t, m, "u1")
+	assert.NoError(t, err)
+	assert.False(t, rsp.GetKicked())
+	assert.Equal(t, []string{"u1"}, r.calls)
+}
+
+func TestHandlerWrapperPanic(t *testing.T) {
+	m, r := newRoomServer(t, func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, req *Request) (interface{}, error) {
+			panic("boom")
+		}
+	})
+	_, err := kick(t, m, "u1")
+	assert.Equal(t, int32(http.StatusBadRequest), e.Parse(err.Error()).Code)
+	assert.Empty(t, r.calls)
+}
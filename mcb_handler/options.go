@@ -3,7 +3,10 @@ package mcb_handler
 import (
 	"context"
 	"github.com/micro/go-micro/v2/client"
+	"github.com/wolfplus2048/mcbeam-plus/message"
+	"github.com/wolfplus2048/mcbeam-plus/route"
 	"github.com/wolfplus2048/mcbeam-plus/serialize"
+	"github.com/wolfplus2048/mcbeam-plus/session"
 )
 
 type Options struct {
@@ -12,7 +15,22 @@ type Options struct {
 	rpcClient    client.Client
 	HdlrWrappers []HandlerWrapper
 }
-type HandlerFunc func(context.Context, interface{}) error
+
+// Request is the call of a component method seen by the handler wrappers
+type Request struct {
+	Route   *route.Route
+	Type    message.Type     // message.Request or message.Notify
+	Arg     interface{}      // decoded argument, nil when the method takes none
+	Session *session.Session // nil on sys rpcs
+}
+
+// HandlerFunc calls a component method, it returns the result sent back.
+// It was func(context.Context, interface{}) error before wrappers were
+// run, those must take the Request and return the result now.
+type HandlerFunc func(ctx context.Context, req *Request) (interface{}, error)
+
+// HandlerWrapper wraps the calls to the component methods, it can return
+// without calling the wrapped HandlerFunc
 type HandlerWrapper func(HandlerFunc) HandlerFunc
 
 type Option func(options *Options)
@@ -32,8 +50,19 @@ func Serializer(s serialize.Serializer) Option {
 		o.serializer = s
 	}
 }
+
+// WrapHandler adds a wrapper around the component methods, the first one
+// added is the outermost
 func WrapHandler(w HandlerWrapper) Option {
 	return func(o *Options) {
 		o.HdlrWrappers = append(o.HdlrWrappers, w)
 	}
 }
+
+// HandlerWrappers replaces the wrappers around the component methods, the
+// first one is the outermost
+func HandlerWrappers(w ...HandlerWrapper) Option {
+	return func(o *Options) {
+		o.HdlrWrappers = w
+	}
+}
@@ -10,4 +10,6 @@ type McbAppHandler interface {
 	Init(opts ...Option)
 	Call(ctx context.Context, req *proto_mcbeam.Request, res *proto_mcbeam.Response) error
 	Handle(handler interface{}, opt ...component.HandlerOption) error
+	// Metadata the server registers with to publish its client routes
+	Metadata() (map[string]string, error)
 }
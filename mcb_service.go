@@ -6,8 +6,17 @@ import (
 	"github.com/micro/go-micro/v2/logger"
 	"github.com/micro/go-micro/v2/server"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/wolfplus2048/mcbeam-plus/agent"
 	"github.com/wolfplus2048/mcbeam-plus/component"
+	"github.com/wolfplus2048/mcbeam-plus/constants"
+	"github.com/wolfplus2048/mcbeam-plus/gateway"
+	"github.com/wolfplus2048/mcbeam-plus/group"
+	"github.com/wolfplus2048/mcbeam-plus/mcb_handler"
 	"github.com/wolfplus2048/mcbeam-plus/mcb_server/grpc"
+	"github.com/wolfplus2048/mcbeam-plus/modules"
+	"github.com/wolfplus2048/mcbeam-plus/protos"
+	"github.com/wolfplus2048/mcbeam-plus/serialize/protobuf"
+	"github.com/wolfplus2048/mcbeam-plus/session"
 	"github.com/wolfplus2048/mcbeam-plus/wrapper"
 	"github.com/micro/go-plugins/wrapper/monitoring/prometheus/v2"
 	"net"
@@ -21,12 +30,20 @@ func init() {
 	logger.Init(logger.WithLevel(logger.DebugLevel))
 }
 
+// app is the last initialized service, package level apis like
+// PushToUser go through it
+var app *mcbService
+
 type mcbService struct {
 	opts Options
 	sync.RWMutex
-	started  bool
-	handlers []component.Component
-	modules  []Module
+	started        bool
+	handlers       []component.Component
+	modules        []Module
+	bindingStorage *modules.BindingStorage
+	uniqueSession  *modules.UniqueSession
+	admin          *modules.Admin
+	groups         *group.Groups
 }
 
 func newMcbService(opt ...Option) Service {
@@ -42,7 +59,9 @@ func newMcbService(opt ...Option) Service {
 }
 
 func (t *mcbService) Register(handler component.Component, opts ...server.HandlerOption) {
-	//t.opts.McbAppHandler.Handle(handler, opts...)
+	if err := t.opts.McbAppHandler.Handle(handler); err != nil {
+		logger.Errorf("Failed to register component: %s", err.Error())
+	}
 	s := t.opts.Service.Server()
 	s.Handle(s.NewHandler(handler, opts...))
 	t.handlers = append(t.handlers, handler)
@@ -100,12 +119,71 @@ func (t *mcbService) Init(opts ...Option) error {
 		),
 	)
 	t.opts.Service.Init(srvOpt...)
+	if t.opts.PushFlushWindow > 0 {
+		agent.SetPushBatching(t.opts.PushFlushWindow, t.opts.PushBatchSize)
+	}
+
+	hdlrOpts := []mcb_handler.Option{
+		mcb_handler.WithName(t.opts.Name),
+		mcb_handler.RpcClient(t.opts.Service.Client()),
+		mcb_handler.Serializer(protobuf.NewSerializer()),
+	}
+	if len(t.opts.McbHdlrWrappers) > 0 {
+		hdlrOpts = append(hdlrOpts, mcb_handler.HandlerWrappers(t.opts.McbHdlrWrappers...))
+	}
+	t.opts.McbAppHandler.Init(hdlrOpts...)
+
+	if err := proto_mcbeam.RegisterMcbAppHandler(t.opts.Service.Server(), t.opts.McbAppHandler); err != nil {
+		return err
+	}
+	err := micro.RegisterSubscriber(constants.SessionCloseTopic, t.opts.Service.Server(), session.HandleRemoteSessionClose)
+	if err != nil {
+		return err
+	}
+	err = micro.RegisterSubscriber(constants.SessionInvalidateTopic, t.opts.Service.Server(), agent.HandleSessionInvalidate)
+	if err != nil {
+		return err
+	}
 
-	//t.opts.McbAppHandler.Init(
-	//	mcb_handler.RpcClient(t.opts.Service.Client()),
-	//	mcb_handler.Serializer(protobuf.NewSerializer()))
+	if t.opts.Store != nil && t.bindingStorage == nil {
+		t.bindingStorage = modules.NewBindingStorage(t.opts.Store, t.opts.Service.Server(), t.opts.BindingLease)
+		t.modules = append(t.modules, t.bindingStorage)
+	}
+	if t.opts.UniqueSession != modules.UniqueAllow && t.uniqueSession == nil {
+		t.uniqueSession = modules.NewUniqueSession(t.opts.UniqueSession, t.bindingStorage, t.opts.Service.Client())
+		t.modules = append(t.modules, t.uniqueSession)
+	}
+	if t.opts.Admin && t.admin == nil {
+		t.admin = modules.NewAdmin(t.opts.AdminAddress, t.opts.AdminToken, t.opts.Service.Server(), t.opts.Service.Client(), t.bindingStorage)
+		t.modules = append(t.modules, t.admin)
+	}
+	if t.opts.GroupLease > 0 && t.opts.GroupService == nil {
+		if t.opts.Store == nil {
+			return constants.ErrNoGroupStore
+		}
+		t.opts.GroupService = group.NewStoreService(t.opts.Store, t.opts.GroupLease)
+	}
+	if t.opts.GroupService != nil && t.groups == nil {
+		t.groups = group.New(t.opts.GroupService, group.NewPusher(t.bindingStorage, t.opts.Service.Client()))
+		t.modules = append(t.modules, t.groups)
+	}
+	app = t
 
-	//proto_mcbeam.RegisterMcbAppHandler(t.opts.Service.Server(), t.opts.McbAppHandler)
+	if t.opts.Gateway != nil {
+		gwOpts := []gateway.Option{
+			gateway.Client(t.opts.Service.Client()),
+			gateway.Server(t.opts.Service.Server()),
+		}
+		if len(t.opts.Gateway.Options().Address) == 0 {
+			gwOpts = append(gwOpts, gateway.Address(DefaultClientAddress))
+		}
+		if t.opts.Gateway.Options().HeartbeatInterval == 0 {
+			gwOpts = append(gwOpts, gateway.Heartbeat(DefaultHeartbeatTime))
+		}
+		if err := t.opts.Gateway.Init(gwOpts...); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -124,6 +202,9 @@ func (t *mcbService) start() error {
 	if t.started {
 		return nil
 	}
+	if err := t.publishRoutes(); err != nil {
+		return err
+	}
 	for _, v := range t.handlers {
 		v.Init()
 	}
@@ -135,9 +216,11 @@ func (t *mcbService) start() error {
 		}
 	}
 
-	//if t.opts.Gateway != nil {
-	//	t.opts.Gateway.Start()
-	//}
+	if t.opts.Gateway != nil {
+		if err := t.opts.Gateway.Start(); err != nil {
+			return err
+		}
+	}
 	if t.opts.Scheduler != nil {
 		t.opts.Scheduler.Start()
 	}
@@ -155,6 +238,24 @@ func (t *mcbService) start() error {
 	return nil
 }
 
+// publishRoutes adds the client routes and their schema to the metadata
+// the server registers with, for the gateways of the cluster
+func (t *mcbService) publishRoutes() error {
+	routes, err := t.opts.McbAppHandler.Metadata()
+	if err != nil {
+		return err
+	}
+	s := t.opts.Service.Server()
+	md := make(map[string]string)
+	for k, v := range s.Options().Metadata {
+		md[k] = v
+	}
+	for k, v := range routes {
+		md[k] = v
+	}
+	return s.Init(server.Metadata(md))
+}
+
 func (t *mcbService) stop() error {
 	t.Lock()
 	defer t.Unlock()
@@ -170,9 +271,11 @@ func (t *mcbService) stop() error {
 		v.BeforeShutdown()
 	}
 
-	//if t.opts.Gateway != nil {
-	//	t.opts.Gateway.Stop()
-	//}
+	if t.opts.Gateway != nil {
+		if err := t.opts.Gateway.Stop(); err != nil {
+			logger.Errorf("Failed to stop gateway: %s", err.Error())
+		}
+	}
 	if t.opts.Scheduler != nil {
 		t.opts.Scheduler.Stop()
 	}
@@ -32,6 +32,14 @@ func GetSessionFromCtx(ctx context.Context) *session.Session {
 	}
 	return sessionVal.(*session.Session)
 }
+
+// GetMessageIDFromCtx retrieves the id of the client request being handled,
+// handlers returning constants.ErrResponseDeferred respond later with it
+func GetMessageIDFromCtx(ctx context.Context) uint {
+	mid, _ := ctx.Value(constants.MessageIDCtxKey).(uint)
+	return mid
+}
+
 func RPC(ctx context.Context, c client.Client, routeStr string, arg proto.Message, replay proto.Message) error {
 	route, err := route.Decode(routeStr)
 	if err != nil {
@@ -23,7 +23,10 @@ package message
 import (
 	"errors"
 	"fmt"
+	"math"
+	"sort"
 	"strings"
+	"sync"
 )
 
 // Type represents the type of message, which could be Request/Notify/Response/Push
@@ -54,8 +57,9 @@ var types = map[Type]string{
 }
 
 var (
-	routes = make(map[string]uint16) // route map to code
-	codes  = make(map[uint16]string) // code map to route
+	dictMutex sync.RWMutex
+	routes    = make(map[string]uint16) // route map to code
+	codes     = make(map[uint16]string) // code map to route
 )
 
 // Errors that could be occurred in message codec
@@ -105,11 +109,15 @@ func invalidType(t Type) bool {
 
 }
 
-// SetDictionary set routes map which be used to compress route.
+// SetDictionary set routes map which be used to compress route. The
+// codes already in the dictionary, set by hand or by AddRoutes, are kept
+// since clients may have them, a route or a code given twice is an error.
 func SetDictionary(dict map[string]uint16) error {
 	if dict == nil {
 		return nil
 	}
+	dictMutex.Lock()
+	defer dictMutex.Unlock()
 
 	for route, code := range dict {
 		r := strings.TrimSpace(route)
@@ -131,9 +139,68 @@ func SetDictionary(dict map[string]uint16) error {
 	return nil
 }
 
-// GetDictionary gets the routes map which is used to compress route.
+// AddRoutes adds routes to the dictionary without choosing their codes.
+// Routes that have a code keep it, so clients that got the dictionary
+// before still decode and encode the same routes. New routes get the
+// lowest free codes, given in the lexical order of the routes, so the
+// same set of routes added at once always gets the same codes.
+func AddRoutes(rs ...string) error {
+	dictMutex.Lock()
+	defer dictMutex.Unlock()
+	added := make([]string, 0, len(rs))
+	for _, route := range rs {
+		r := strings.TrimSpace(route)
+		if _, ok := routes[r]; ok || r == "" {
+			continue
+		}
+		added = append(added, r)
+	}
+	return assignCodes(added)
+}
+
+func assignCodes(rs []string) error {
+	sort.Strings(rs)
+	code := 1
+	for _, r := range rs {
+		for ; code <= math.MaxUint16; code++ {
+			if _, ok := codes[uint16(code)]; !ok {
+				break
+			}
+		}
+		if code > math.MaxUint16 {
+			return fmt.Errorf("no code left for route %s", r)
+		}
+		routes[r] = uint16(code)
+		codes[uint16(code)] = r
+		code++
+	}
+	return nil
+}
+
+// GetDictionary gets a copy of the routes map which is used to compress
+// route.
 func GetDictionary() map[string]uint16 {
-	return routes
+	dictMutex.RLock()
+	defer dictMutex.RUnlock()
+	dict := make(map[string]uint16, len(routes))
+	for r, code := range routes {
+		dict[r] = code
+	}
+	return dict
+}
+
+func routeCode(route string) (uint16, bool) {
+	dictMutex.RLock()
+	defer dictMutex.RUnlock()
+	code, ok := routes[route]
+	return code, ok
+}
+
+func codeRoute(code uint16) (string, bool) {
+	dictMutex.RLock()
+	defer dictMutex.RUnlock()
+	route, ok := codes[code]
+	return route, ok
 }
 
 func (t *Type) String() string {
@@ -34,14 +34,29 @@ type Encoder interface {
 // MessagesEncoder implements MessageEncoder interface
 type MessagesEncoder struct {
 	DataCompression bool
+	routeCode       func(route string) (uint16, bool) // codes of the compressed routes
 }
 
-// NewMessagesEncoder returns a new message encoder
+// NewMessagesEncoder returns a new message encoder, it compresses the
+// routes of the dictionary
 func NewMessagesEncoder(dataCompression bool) *MessagesEncoder {
-	me := &MessagesEncoder{dataCompression}
+	me := &MessagesEncoder{DataCompression: dataCompression, routeCode: routeCode}
 	return me
 }
 
+// WithDictionary returns an encoder only compressing the routes of dict,
+// the dictionary a client got at handshake, so routes added since are
+// sent in full to the client. dict must not be changed afterwards.
+func (me *MessagesEncoder) WithDictionary(dict map[string]uint16) *MessagesEncoder {
+	return &MessagesEncoder{
+		DataCompression: me.DataCompression,
+		routeCode: func(route string) (uint16, bool) {
+			code, ok := dict[route]
+			return code, ok
+		},
+	}
+}
+
 // IsCompressionEnabled returns wether the compression is enabled or not
 func (me *MessagesEncoder) IsCompressionEnabled() bool {
 	return me.DataCompression
@@ -68,7 +83,11 @@ func (me *MessagesEncoder) Encode(message *Message) ([]byte, error) {
 	buf := make([]byte, 0)
 	flag := byte(message.Type) << 1
 
-	code, compressed := routes[message.Route]
+	lookup := me.routeCode
+	if lookup == nil {
+		lookup = routeCode
+	}
+	code, compressed := lookup(message.Route)
 	if compressed {
 		flag |= msgRouteCompressMask
 	}
@@ -162,7 +181,7 @@ func Decode(data []byte) (*Message, error) {
 		if flag&msgRouteCompressMask == 1 {
 			m.compressed = true
 			code := binary.BigEndian.Uint16(data[offset:(offset + 2)])
-			route, ok := codes[code]
+			route, ok := codeRoute(code)
 			if !ok {
 				return nil, ErrRouteInfoNotFound
 			}
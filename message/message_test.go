@@ -0,0 +1,104 @@
+package message
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func resetDictionary() {
+	routes = make(map[string]uint16)
+	codes = make(map[uint16]string)
+}
+
+func TestAddRoutesIsStable(t *testing.T) {
+	resetDictionary()
+	defer resetDictionary()
+
+	assert.NoError(t, AddRoutes("room.handler.join", "chat.handler.send"))
+	first := map[string]uint16{}
+	for r, c := range GetDictionary() {
+		first[r] = c
+	}
+
+	resetDictionary()
+	assert.NoError(t, AddRoutes("chat.handler.send"))
+	assert.NoError(t, AddRoutes("room.handler.join", "chat.handler.send"))
+	assert.Equal(t, first, GetDictionary())
+	assert.Equal(t, uint16(1), first["chat.handler.send"])
+	assert.Equal(t, uint16(2), first["room.handler.join"])
+}
+
+func TestAddRoutesKeepsManualCodes(t *testing.T) {
+	resetDictionary()
+	defer resetDictionary()
+
+	assert.NoError(t, SetDictionary(map[string]uint16{"a.b.d": 1, "x.y.z": 2}))
+	assert.NoError(t, AddRoutes("a.b.c", "a.b.d"))
+
+	dict := GetDictionary()
+	assert.Equal(t, uint16(1), dict["a.b.d"])
+	assert.Equal(t, uint16(2), dict["x.y.z"])
+	assert.Equal(t, uint16(3), dict["a.b.c"])
+	assert.Equal(t, "a.b.c", codes[3])
+	assert.Len(t, codes, 3)
+}
+
+func TestSetDictionaryKeepsGivenCodes(t *testing.T) {
+	resetDictionary()
+	defer resetDictionary()
+
+	assert.NoError(t, AddRoutes("a.b.c", "a.b.d"))
+	assert.NoError(t, SetDictionary(map[string]uint16{"x.y.z": 3}))
+	assert.Error(t, SetDictionary(map[string]uint16{"a.b.d": 5}))
+	assert.Error(t, SetDictionary(map[string]uint16{"q.r.s": 1}))
+
+	assert.Equal(t, map[string]uint16{"a.b.c": 1, "a.b.d": 2, "x.y.z": 3}, GetDictionary())
+}
+
+func TestAddRoutesKeepsGivenCodes(t *testing.T) {
+	resetDictionary()
+	defer resetDictionary()
+
+	assert.NoError(t, AddRoutes("room.handler.join"))
+	// a server with routes sorted before the known ones joins the cluster
+	assert.NoError(t, AddRoutes("chat.handler.send", "room.handler.join"))
+
+	dict := GetDictionary()
+	assert.Equal(t, uint16(1), dict["room.handler.join"])
+	assert.Equal(t, uint16(2), dict["chat.handler.send"])
+}
+
+func TestEncodeWithDictionary(t *testing.T) {
+	resetDictionary()
+	defer resetDictionary()
+
+	assert.NoError(t, AddRoutes("room.handler.join"))
+	snapshot := GetDictionary()
+	// found by a later refresh, the client does not know its code
+	assert.NoError(t, AddRoutes("chat.handler.send"))
+
+	me := NewMessagesEncoder(false).WithDictionary(snapshot)
+	tables := []struct {
+		route      string
+		compressed bool
+	}{
+		{"room.handler.join", true},
+		{"chat.handler.send", false},
+	}
+	for _, table := range tables {
+		b, err := me.Encode(&Message{Type: Push, Route: table.route, Data: []byte("x")})
+		assert.NoError(t, err)
+		m, err := Decode(b)
+		assert.NoError(t, err)
+		assert.Equal(t, table.route, m.Route)
+		assert.Equal(t, table.compressed, m.compressed)
+	}
+
+	// the shared encoder compresses every route of the dictionary
+	b, err := NewMessagesEncoder(false).Encode(&Message{Type: Push, Route: "chat.handler.send"})
+	assert.NoError(t, err)
+	m, err := Decode(b)
+	assert.NoError(t, err)
+	assert.True(t, m.compressed)
+}
@@ -0,0 +1,262 @@
+package modules
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/micro/go-micro/v2/client"
+	"github.com/micro/go-micro/v2/client/selector"
+	e "github.com/micro/go-micro/v2/errors"
+	"github.com/micro/go-micro/v2/logger"
+	"github.com/micro/go-micro/v2/server"
+	"github.com/wolfplus2048/mcbeam-plus/constants"
+	"github.com/wolfplus2048/mcbeam-plus/protos"
+	"github.com/wolfplus2048/mcbeam-plus/session"
+	"github.com/wolfplus2048/mcbeam-plus/util"
+)
+
+// Admin serves the McbAdmin rpc and its http json counterpart, to find
+// out who is online and kick them. Sessions are listed on the frontend
+// serving the request, a session asked by uid is looked for on the
+// frontend it is bound on when there is a binding storage.
+type Admin struct {
+	address        string
+	token          string
+	server         server.Server
+	client         client.Client
+	bindingStorage *BindingStorage
+	name           string
+	frontendID     string
+	httpServer     *http.Server
+}
+
+// NewAdmin returns the admin module, the http endpoint listens on address
+// unless it is empty. Its requests must carry token as a bearer token, an
+// endpoint without a token only listens on loopback. bs can be nil.
+func NewAdmin(address, token string, srv server.Server, c client.Client, bs *BindingStorage) *Admin {
+	return &Admin{
+		address:        address,
+		token:          token,
+		server:         srv,
+		client:         c,
+		bindingStorage: bs,
+	}
+}
+
+// Init registers the McbAdmin handler and starts the http endpoint
+func (a *Admin) Init() error {
+	opts := a.server.Options()
+	a.name = opts.Name
+	a.frontendID = opts.Name + "-" + opts.Id
+	if err := proto_mcbeam.RegisterMcbAdminHandler(a.server, a); err != nil {
+		return err
+	}
+	if a.address == "" {
+		return nil
+	}
+	address, err := a.listenAddress()
+	if err != nil {
+		return err
+	}
+	ln, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+	a.httpServer = &http.Server{Handler: a.authorize(a.httpHandler())}
+	logger.Infof("Admin [http] Listening on %s", ln.Addr().String())
+	go func() {
+		if err := a.httpServer.Serve(ln); err != nil && err != http.ErrServerClosed {
+			logger.Errorf("Admin http server stopped: %s", err.Error())
+		}
+	}()
+	return nil
+}
+
+// listenAddress returns the address of the http endpoint, without a token
+// a missing host is the loopback one and other hosts are refused
+func (a *Admin) listenAddress() (string, error) {
+	if a.token != "" {
+		return a.address, nil
+	}
+	host, port, err := net.SplitHostPort(a.address)
+	if err != nil {
+		return "", err
+	}
+	if host == "" {
+		return net.JoinHostPort("127.0.0.1", port), nil
+	}
+	if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+		return "", constants.ErrAdminNotLoopback
+	}
+	return a.address, nil
+}
+
+// AfterInit is called after the module is initialized
+func (a *Admin) AfterInit() {}
+
+// BeforeShutdown is called before the module is shut down
+func (a *Admin) BeforeShutdown() {}
+
+// Shutdown stops the http endpoint
+func (a *Admin) Shutdown() error {
+	if a.httpServer != nil {
+		return a.httpServer.Close()
+	}
+	return nil
+}
+
+// ListSessions lists the sessions of this frontend matching the filter
+func (a *Admin) ListSessions(ctx context.Context, in *proto_mcbeam.SessionFilter, out *proto_mcbeam.SessionList) error {
+	session.Range(func(s *session.Session) bool {
+		if matches(in, s) {
+			out.Sessions = append(out.Sessions, a.info(s))
+		}
+		return in.GetLimit() <= 0 || len(out.Sessions) < int(in.GetLimit())
+	})
+	return nil
+}
+
+// CountSessions counts the sessions of this frontend matching the filter
+func (a *Admin) CountSessions(ctx context.Context, in *proto_mcbeam.SessionFilter, out *proto_mcbeam.SessionCount) error {
+	session.Range(func(s *session.Session) bool {
+		if matches(in, s) {
+			out.Count++
+		}
+		return true
+	})
+	return nil
+}
+
+// GetSession returns the session with the id or the uid of the query
+func (a *Admin) GetSession(ctx context.Context, in *proto_mcbeam.SessionQuery, out *proto_mcbeam.SessionInfo) error {
+	frontendID, err := a.owner(in)
+	if err != nil {
+		return err
+	}
+	if frontendID != a.frontendID {
+		rsp, err := a.remote(frontendID).GetSession(ctx, in, a.selectOption(frontendID))
+		if err != nil {
+			return err
+		}
+		proto.Merge(out, rsp)
+		return nil
+	}
+	s := a.lookup(in)
+	if s == nil {
+		return e.NotFound(a.name, "%s", constants.ErrSessionNotFound.Error())
+	}
+	proto.Merge(out, a.info(s))
+	return nil
+}
+
+// KickSession kicks the session with the id or the uid of the query, the
+// reason of the query is sent to the client
+func (a *Admin) KickSession(ctx context.Context, in *proto_mcbeam.SessionQuery, out *proto_mcbeam.KickAnswer) error {
+	frontendID, err := a.owner(in)
+	if err != nil {
+		return err
+	}
+	if frontendID != a.frontendID {
+		rsp, err := a.remote(frontendID).KickSession(ctx, in, a.selectOption(frontendID))
+		if err != nil {
+			return err
+		}
+		out.Kicked = rsp.GetKicked()
+		return nil
+	}
+	s := a.lookup(in)
+	if s == nil {
+		return e.NotFound(a.name, "%s", constants.ErrSessionNotFound.Error())
+	}
+	if in.GetReason() != "" {
+		s.SetCloseReason(in.GetReason())
+	}
+	if err := s.Kick(ctx); err != nil {
+		return e.InternalServerError(a.name, "%s", err.Error())
+	}
+	out.Kicked = true
+	return nil
+}
+
+// owner returns the id of the frontend the queried session lives on
+func (a *Admin) owner(in *proto_mcbeam.SessionQuery) (string, error) {
+	if in.GetFrontendID() != "" {
+		return in.GetFrontendID(), nil
+	}
+	if in.GetUid() == "" || a.bindingStorage == nil || session.GetSessionByUID(in.GetUid()) != nil {
+		return a.frontendID, nil
+	}
+	frontendID, err := a.bindingStorage.GetUserFrontendID(in.GetUid())
+	if err == constants.ErrBindingNotFound {
+		return "", e.NotFound(a.name, "%s, uid: %s", err.Error(), in.GetUid())
+	}
+	if err != nil {
+		return "", e.InternalServerError(a.name, "%s", err.Error())
+	}
+	return frontendID, nil
+}
+
+func (a *Admin) lookup(in *proto_mcbeam.SessionQuery) *session.Session {
+	if in.GetUid() != "" {
+		return session.GetSessionByUID(in.GetUid())
+	}
+	return session.GetSessionByID(in.GetId())
+}
+
+func (a *Admin) remote(frontendID string) proto_mcbeam.McbAdminService {
+	return proto_mcbeam.NewMcbAdminService(strings.SplitN(frontendID, "-", 2)[0], a.client)
+}
+
+func (a *Admin) selectOption(frontendID string) client.CallOption {
+	return client.WithSelectOption(selector.WithStrategy(util.Select(frontendID)))
+}
+
+func (a *Admin) info(s *session.Session) *proto_mcbeam.SessionInfo {
+	info := &proto_mcbeam.SessionInfo{
+		Id:         s.ID(),
+		Uid:        s.UID(),
+		FrontendID: a.frontendID,
+		CreatedAt:  s.CreatedAt(),
+		LastTime:   s.LastTime(),
+	}
+	if addr := s.RemoteAddr(); addr != nil {
+		info.RemoteAddr = addr.String()
+	}
+	if hd := s.GetHandshakeData(); hd != nil {
+		info.Platform = hd.Sys.Platform
+		info.ClientVersion = hd.Sys.Version
+	}
+	data, err := json.Marshal(s.CopyData())
+	if err != nil {
+		logger.Errorf("Failed to encode data of session %d: %s", s.ID(), err.Error())
+	}
+	info.Data = data
+	return info
+}
+
+func matches(f *proto_mcbeam.SessionFilter, s *session.Session) bool {
+	if !strings.HasPrefix(s.UID(), f.GetUidPrefix()) {
+		return false
+	}
+	if f.GetPlatform() != "" {
+		hd := s.GetHandshakeData()
+		if hd == nil || hd.Sys.Platform != f.GetPlatform() {
+			return false
+		}
+	}
+	if f.GetRemoteIP() != "" {
+		addr := s.RemoteAddr()
+		if addr == nil {
+			return false
+		}
+		host, _, err := net.SplitHostPort(addr.String())
+		if err != nil || host != f.GetRemoteIP() {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,128 @@
+package modules
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	e "github.com/micro/go-micro/v2/errors"
+	"github.com/wolfplus2048/mcbeam-plus/protos"
+)
+
+// sessionJSON embeds the session data as json rather than base64
+type sessionJSON struct {
+	*proto_mcbeam.SessionInfo
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+func newSessionJSON(info *proto_mcbeam.SessionInfo) sessionJSON {
+	return sessionJSON{SessionInfo: info, Data: info.GetData()}
+}
+
+// httpHandler serves
+//
+//	GET  /sessions?uidPrefix=&platform=&remoteIP=&limit=
+//	GET  /sessions/count?uidPrefix=&platform=&remoteIP=
+//	GET  /session?id=|uid=&frontendID=
+//	POST /session/kick?id=|uid=&frontendID=&reason=
+func (a *Admin) httpHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/sessions", func(w http.ResponseWriter, r *http.Request) {
+		out := &proto_mcbeam.SessionList{}
+		if err := a.ListSessions(r.Context(), filterFromQuery(r), out); err != nil {
+			writeError(w, err)
+			return
+		}
+		sessions := make([]sessionJSON, 0, len(out.GetSessions()))
+		for _, info := range out.GetSessions() {
+			sessions = append(sessions, newSessionJSON(info))
+		}
+		writeJSON(w, map[string]interface{}{"sessions": sessions})
+	})
+	mux.HandleFunc("/sessions/count", func(w http.ResponseWriter, r *http.Request) {
+		out := &proto_mcbeam.SessionCount{}
+		if err := a.CountSessions(r.Context(), filterFromQuery(r), out); err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, map[string]interface{}{"count": out.GetCount()})
+	})
+	mux.HandleFunc("/session", func(w http.ResponseWriter, r *http.Request) {
+		out := &proto_mcbeam.SessionInfo{}
+		if err := a.GetSession(r.Context(), queryFromRequest(r), out); err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, newSessionJSON(out))
+	})
+	mux.HandleFunc("/session/kick", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeError(w, e.MethodNotAllowed(a.name, "kick needs a POST"))
+			return
+		}
+		out := &proto_mcbeam.KickAnswer{}
+		if err := a.KickSession(r.Context(), queryFromRequest(r), out); err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, map[string]interface{}{"kicked": out.GetKicked()})
+	})
+	return mux
+}
+
+// authorize rejects the requests without the bearer token of the admin,
+// if it has one
+func (a *Admin) authorize(h http.Handler) http.Handler {
+	if a.token == "" {
+		return h
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(a.token)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, e.Unauthorized(a.name, "invalid admin token"))
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+func filterFromQuery(r *http.Request) *proto_mcbeam.SessionFilter {
+	q := r.URL.Query()
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	return &proto_mcbeam.SessionFilter{
+		UidPrefix: q.Get("uidPrefix"),
+		Platform:  q.Get("platform"),
+		RemoteIP:  q.Get("remoteIP"),
+		Limit:     int32(limit),
+	}
+}
+
+func queryFromRequest(r *http.Request) *proto_mcbeam.SessionQuery {
+	q := r.URL.Query()
+	id, _ := strconv.ParseInt(q.Get("id"), 10, 64)
+	return &proto_mcbeam.SessionQuery{
+		Id:         id,
+		Uid:        q.Get("uid"),
+		FrontendID: q.Get("frontendID"),
+		Reason:     q.Get("reason"),
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	merr := e.Parse(err.Error())
+	code := int(merr.Code)
+	if code == 0 {
+		code = http.StatusInternalServerError
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(merr)
+}
@@ -0,0 +1,184 @@
+package modules
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/micro/go-micro/v2/client"
+	e "github.com/micro/go-micro/v2/errors"
+	"github.com/micro/go-micro/v2/server"
+	"github.com/micro/go-micro/v2/store/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wolfplus2048/mcbeam-plus/constants"
+	"github.com/wolfplus2048/mcbeam-plus/protos"
+	"github.com/wolfplus2048/mcbeam-plus/session"
+)
+
+// adminClient answers the McbAdmin calls to other frontends
+type adminClient struct {
+	client.Client
+	endpoints []string
+}
+
+func (c *adminClient) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	c.endpoints = append(c.endpoints, req.Service()+"/"+req.Endpoint())
+	in := req.Body().(*proto_mcbeam.SessionQuery)
+	rsp.(*proto_mcbeam.SessionInfo).Uid = in.GetUid()
+	rsp.(*proto_mcbeam.SessionInfo).FrontendID = "gate-2"
+	return nil
+}
+
+func (c *adminClient) NewRequest(service, endpoint string, req interface{}, opts ...client.RequestOption) client.Request {
+	return client.NewClient().NewRequest(service, endpoint, req, opts...)
+}
+
+// newAdmin returns the admin module of frontend gate-1
+func newAdmin(t *testing.T, c client.Client, b *BindingStorage) *Admin {
+	a := NewAdmin("", "", server.NewServer(server.Name("gate"), server.Id("1")), c, b)
+	require.NoError(t, a.Init())
+	return a
+}
+
+// connected returns a session bound to uid with a client on platform and ip
+func connected(t *testing.T, uid, platform, ip string) *session.Session {
+	s := session.New(&entity{addr: &net.TCPAddr{IP: net.ParseIP(ip), Port: 4000}}, true)
+	s.SetHandshakeData(&session.HandshakeData{Sys: session.HandshakeClientData{Platform: platform}})
+	require.NoError(t, s.Bind(context.Background(), uid))
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestAdminFilters(t *testing.T) {
+	a := newAdmin(t, nil, nil)
+	connected(t, "admin-a1", "ios", "10.0.0.1")
+	connected(t, "admin-a2", "web", "10.0.0.2")
+	connected(t, "admin-b1", "ios", "10.0.0.2")
+
+	tables := []struct {
+		name   string
+		filter *proto_mcbeam.SessionFilter
+		uids   []string
+	}{
+		{"prefix", &proto_mcbeam.SessionFilter{UidPrefix: "admin-a"}, []string{"admin-a1", "admin-a2"}},
+		{"platform", &proto_mcbeam.SessionFilter{UidPrefix: "admin-", Platform: "ios"}, []string{"admin-a1", "admin-b1"}},
+		{"remote ip", &proto_mcbeam.SessionFilter{UidPrefix: "admin-", RemoteIP: "10.0.0.2"}, []string{"admin-a2", "admin-b1"}},
+		{"all", &proto_mcbeam.SessionFilter{UidPrefix: "admin-", Platform: "web", RemoteIP: "10.0.0.2"}, []string{"admin-a2"}},
+		{"none", &proto_mcbeam.SessionFilter{UidPrefix: "admin-", Platform: "android"}, nil},
+	}
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			list := &proto_mcbeam.SessionList{}
+			require.NoError(t, a.ListSessions(context.Background(), table.filter, list))
+			var uids []string
+			for _, info := range list.GetSessions() {
+				assert.Equal(t, "gate-1", info.GetFrontendID())
+				uids = append(uids, info.GetUid())
+			}
+			assert.ElementsMatch(t, table.uids, uids)
+
+			count := &proto_mcbeam.SessionCount{}
+			require.NoError(t, a.CountSessions(context.Background(), table.filter, count))
+			assert.Equal(t, int64(len(table.uids)), int64(count.GetCount()))
+		})
+	}
+
+	list := &proto_mcbeam.SessionList{}
+	require.NoError(t, a.ListSessions(context.Background(), &proto_mcbeam.SessionFilter{UidPrefix: "admin-", Limit: 2}, list))
+	assert.Len(t, list.GetSessions(), 2)
+}
+
+func TestAdminRemoteOwner(t *testing.T) {
+	st := memory.NewStore()
+	other := NewBindingStorage(st, nil, 0)
+	other.frontendID = "gate-2"
+	require.NoError(t, other.PutBinding("owner-u2"))
+	c := &adminClient{}
+	a := newAdmin(t, c, newBindingStorage(t, st, "1", 0))
+	connected(t, "owner-u1", "web", "10.0.0.1")
+
+	// sessions of this frontend are answered locally
+	info := &proto_mcbeam.SessionInfo{}
+	require.NoError(t, a.GetSession(context.Background(), &proto_mcbeam.SessionQuery{Uid: "owner-u1"}, info))
+	assert.Equal(t, "gate-1", info.GetFrontendID())
+	assert.Empty(t, c.endpoints)
+
+	// the others by the frontend of their binding
+	info = &proto_mcbeam.SessionInfo{}
+	require.NoError(t, a.GetSession(context.Background(), &proto_mcbeam.SessionQuery{Uid: "owner-u2"}, info))
+	assert.Equal(t, "gate-2", info.GetFrontendID())
+	assert.Equal(t, []string{"gate/McbAdmin.GetSession"}, c.endpoints)
+
+	err := a.GetSession(context.Background(), &proto_mcbeam.SessionQuery{Uid: "owner-u3"}, &proto_mcbeam.SessionInfo{})
+	assert.Equal(t, int32(http.StatusNotFound), e.Parse(err.Error()).Code)
+}
+
+func TestWriteError(t *testing.T) {
+	tables := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"not found", e.NotFound("gate", "session not found"), http.StatusNotFound},
+		{"method", e.MethodNotAllowed("gate", "kick needs a POST"), http.StatusMethodNotAllowed},
+		{"plain", errors.New("broken"), http.StatusInternalServerError},
+	}
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			writeError(w, table.err)
+			assert.Equal(t, table.code, w.Code)
+			assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+		})
+	}
+}
+
+func TestAdminToken(t *testing.T) {
+	a := NewAdmin(":0", "secret", nil, nil, nil)
+	h := a.authorize(a.httpHandler())
+
+	tables := []struct {
+		name   string
+		header string
+		code   int
+	}{
+		{"missing", "", http.StatusUnauthorized},
+		{"wrong", "Bearer nope", http.StatusUnauthorized},
+		{"valid", "Bearer secret", http.StatusOK},
+	}
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/sessions/count?uidPrefix=token-", nil)
+			if table.header != "" {
+				r.Header.Set("Authorization", table.header)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			assert.Equal(t, table.code, w.Code)
+		})
+	}
+}
+
+func TestAdminListenAddress(t *testing.T) {
+	tables := []struct {
+		address, token string
+		listen         string
+		err            error
+	}{
+		{":8080", "", "127.0.0.1:8080", nil},
+		{"localhost:8080", "", "localhost:8080", nil},
+		{"[::1]:8080", "", "[::1]:8080", nil},
+		{"0.0.0.0:8080", "", "", constants.ErrAdminNotLoopback},
+		{"10.0.0.1:8080", "", "", constants.ErrAdminNotLoopback},
+		{":8080", "secret", ":8080", nil},
+	}
+	for _, table := range tables {
+		listen, err := NewAdmin(table.address, table.token, nil, nil, nil).listenAddress()
+		assert.Equal(t, table.err, err)
+		assert.Equal(t, table.listen, listen)
+	}
+}
@@ -0,0 +1,199 @@
+package modules
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/micro/go-micro/v2/logger"
+	"github.com/micro/go-micro/v2/server"
+	"github.com/micro/go-micro/v2/store"
+	"github.com/wolfplus2048/mcbeam-plus/constants"
+	"github.com/wolfplus2048/mcbeam-plus/session"
+)
+
+const bindingPrefix = "mcbeam/bindings/"
+
+// DefaultBindingLease is how long a binding stays in the store once the
+// frontend that wrote it stopped renewing it
+const DefaultBindingLease = 30 * time.Second
+
+// BindingStorage keeps in the store which frontend each uid is bound on,
+// so any server can reach an online user. Bindings are written with a
+// lease the frontend renews, they are dropped once it dies.
+type BindingStorage struct {
+	store      store.Store
+	server     server.Server
+	lease      time.Duration
+	frontendID string
+	hooks      []*session.HookHandle
+	mutex      sync.Mutex
+	renewing   map[string]int64 // uid -> id of the session bound here
+	done       chan struct{}
+}
+
+// NewBindingStorage returns a binding storage writing to s, the frontend id
+// of the bindings is the one of srv. Bindings are leased for lease,
+// DefaultBindingLease if it is zero.
+func NewBindingStorage(s store.Store, srv server.Server, lease time.Duration) *BindingStorage {
+	if lease <= 0 {
+		lease = DefaultBindingLease
+	}
+	return &BindingStorage{
+		store:    s,
+		server:   srv,
+		lease:    lease,
+		renewing: make(map[string]int64),
+	}
+}
+
+// Init starts writing the bindings of the frontend sessions
+func (b *BindingStorage) Init() error {
+	opts := b.server.Options()
+	b.frontendID = opts.Name + "-" + opts.Id
+	b.hooks = []*session.HookHandle{
+		session.OnAfterSessionBind(b.onSessionBind),
+		session.OnSessionClose(b.onSessionClose),
+	}
+	done := make(chan struct{})
+	b.done = done
+	go func() {
+		ticker := time.NewTicker(b.lease / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				b.renew()
+			case <-done:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// AfterInit is called after the module is initialized
+func (b *BindingStorage) AfterInit() {}
+
+// BeforeShutdown is called before the module is shut down
+func (b *BindingStorage) BeforeShutdown() {}
+
+// Shutdown stops writing the bindings, they expire with their lease
+func (b *BindingStorage) Shutdown() error {
+	for _, h := range b.hooks {
+		h.Remove()
+	}
+	if b.done != nil {
+		close(b.done)
+		b.done = nil
+	}
+	return nil
+}
+
+// GetUserFrontendID returns the id of the frontend the uid is bound on
+func (b *BindingStorage) GetUserFrontendID(uid string) (string, error) {
+	recs, err := b.store.Read(bindingKey(uid))
+	if err == store.ErrNotFound || (err == nil && len(recs) == 0) {
+		return "", constants.ErrBindingNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(recs[0].Value), nil
+}
+
+// PutBinding binds the uid to this frontend for the lease
+func (b *BindingStorage) PutBinding(uid string) error {
+	return b.store.Write(&store.Record{
+		Key:    bindingKey(uid),
+		Value:  []byte(b.frontendID),
+		Expiry: b.lease,
+	})
+}
+
+// renew rewrites the bindings of the sessions bound here, unless the uid
+// was bound on another frontend since. A binding removed by a concurrent
+// removeBinding is written again.
+func (b *BindingStorage) renew() {
+	b.mutex.Lock()
+	uids := make([]string, 0, len(b.renewing))
+	for uid := range b.renewing {
+		uids = append(uids, uid)
+	}
+	b.mutex.Unlock()
+
+	for _, uid := range uids {
+		frontendID, err := b.GetUserFrontendID(uid)
+		if err != nil && err != constants.ErrBindingNotFound {
+			logger.Errorf("Failed to read binding of uid %s: %s", uid, err.Error())
+			continue
+		}
+		if err == nil && frontendID != b.frontendID {
+			b.forget(uid, 0)
+			continue
+		}
+		if err := b.PutBinding(uid); err != nil {
+			logger.Errorf("Failed to renew binding of uid %s: %s", uid, err.Error())
+		}
+	}
+}
+
+// forget stops renewing the binding of the uid, only when it belongs to
+// the session id unless id is zero
+func (b *BindingStorage) forget(uid string, id int64) bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if bound, ok := b.renewing[uid]; !ok || (id != 0 && bound != id) {
+		return false
+	}
+	delete(b.renewing, uid)
+	return true
+}
+
+// removeBinding removes the binding of the uid, unless the user is bound
+// on another frontend since. The store has no compare-and-delete, a bind
+// on another frontend between the read and the delete loses its binding
+// until that frontend renews it.
+func (b *BindingStorage) removeBinding(uid string) error {
+	frontendID, err := b.GetUserFrontendID(uid)
+	if err == constants.ErrBindingNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if frontendID != b.frontendID {
+		return nil
+	}
+	return b.store.Delete(bindingKey(uid))
+}
+
+func (b *BindingStorage) onSessionBind(ctx context.Context, s *session.Session) error {
+	if !s.IsFrontend {
+		return nil
+	}
+	if err := b.PutBinding(s.UID()); err != nil {
+		return err
+	}
+	b.mutex.Lock()
+	b.renewing[s.UID()] = s.ID()
+	b.mutex.Unlock()
+	return nil
+}
+
+func (b *BindingStorage) onSessionClose(s *session.Session) {
+	if !s.IsFrontend || s.UID() == "" {
+		return
+	}
+	// another session of the uid bound here since keeps the binding
+	if !b.forget(s.UID(), s.ID()) {
+		return
+	}
+	if err := b.removeBinding(s.UID()); err != nil {
+		logger.Errorf("Failed to remove binding of uid %s: %s", s.UID(), err.Error())
+	}
+}
+
+func bindingKey(uid string) string {
+	return bindingPrefix + uid
+}
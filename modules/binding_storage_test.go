@@ -0,0 +1,110 @@
+package modules
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v2/server"
+	"github.com/micro/go-micro/v2/store"
+	"github.com/micro/go-micro/v2/store/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wolfplus2048/mcbeam-plus/constants"
+	"github.com/wolfplus2048/mcbeam-plus/session"
+)
+
+// entity is the network entity of a frontend session in tests
+type entity struct {
+	session.NetworkEntity
+	kicked  bool
+	kickErr error
+	addr    net.Addr
+}
+
+func (e *entity) Kick(ctx context.Context) error { e.kicked = true; return e.kickErr }
+func (e *entity) Close() error                   { return nil }
+func (e *entity) RemoteAddr() net.Addr           { return e.addr }
+
+// newBindingStorage returns a started binding storage of frontend gate-id
+func newBindingStorage(t *testing.T, s store.Store, id string, lease time.Duration) *BindingStorage {
+	b := NewBindingStorage(s, server.NewServer(server.Name("gate"), server.Id(id)), lease)
+	require.NoError(t, b.Init())
+	t.Cleanup(func() { b.Shutdown() })
+	return b
+}
+
+// bound returns a frontend session bound to uid
+func bound(t *testing.T, uid string) *session.Session {
+	s := session.New(&entity{}, true)
+	require.NoError(t, s.Bind(context.Background(), uid))
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestBindingLease(t *testing.T) {
+	st := memory.NewStore()
+	b := newBindingStorage(t, st, "1", 90*time.Millisecond)
+	bound(t, "lease-u1")
+
+	recs, err := st.Read(bindingKey("lease-u1"))
+	require.NoError(t, err)
+	assert.True(t, recs[0].Expiry > 0)
+
+	// renewed while the frontend runs
+	time.Sleep(200 * time.Millisecond)
+	fid, err := b.GetUserFrontendID("lease-u1")
+	assert.NoError(t, err)
+	assert.Equal(t, "gate-1", fid)
+
+	// dropped once it stops
+	b.Shutdown()
+	assert.Eventually(t, func() bool {
+		_, err := b.GetUserFrontendID("lease-u1")
+		return err == constants.ErrBindingNotFound
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestBindingRemove(t *testing.T) {
+	st := memory.NewStore()
+	b := newBindingStorage(t, st, "1", time.Minute)
+	other := NewBindingStorage(st, nil, time.Minute)
+	other.frontendID = "gate-2"
+
+	s := bound(t, "remove-u1")
+	b.onSessionClose(s)
+	_, err := b.GetUserFrontendID("remove-u1")
+	assert.Equal(t, constants.ErrBindingNotFound, err)
+
+	// the uid bound on another frontend since keeps its binding
+	s = bound(t, "remove-u2")
+	require.NoError(t, other.PutBinding("remove-u2"))
+	b.onSessionClose(s)
+	fid, err := b.GetUserFrontendID("remove-u2")
+	assert.NoError(t, err)
+	assert.Equal(t, "gate-2", fid)
+}
+
+func TestBindingRenew(t *testing.T) {
+	st := memory.NewStore()
+	b := newBindingStorage(t, st, "1", time.Minute)
+	other := NewBindingStorage(st, nil, time.Minute)
+	other.frontendID = "gate-2"
+	bound(t, "renew-u1")
+	bound(t, "renew-u2")
+
+	// a delete racing with the bind is written again, the binding of
+	// another frontend is left alone and no longer renewed
+	require.NoError(t, st.Delete(bindingKey("renew-u1")))
+	require.NoError(t, other.PutBinding("renew-u2"))
+	b.renew()
+
+	fid, err := b.GetUserFrontendID("renew-u1")
+	assert.NoError(t, err)
+	assert.Equal(t, "gate-1", fid)
+	fid, err = b.GetUserFrontendID("renew-u2")
+	assert.NoError(t, err)
+	assert.Equal(t, "gate-2", fid)
+	assert.NotContains(t, b.renewing, "renew-u2")
+}
@@ -0,0 +1,110 @@
+package modules
+
+import (
+	"context"
+
+	"github.com/micro/go-micro/v2/client"
+	"github.com/micro/go-micro/v2/logger"
+	"github.com/wolfplus2048/mcbeam-plus/constants"
+	"github.com/wolfplus2048/mcbeam-plus/fanout"
+	"github.com/wolfplus2048/mcbeam-plus/session"
+)
+
+// UniquePolicy tells what to do when a uid binds while it is still bound
+// to another session
+type UniquePolicy int
+
+const (
+	// UniqueAllow lets both sessions live with the same uid
+	UniqueAllow UniquePolicy = iota
+	// UniqueKickOld kicks the session the uid was bound to, the bind fails
+	// when the frontend of that session can not be reached
+	UniqueKickOld
+	// UniqueRejectNew fails the bind of the new session
+	UniqueRejectNew
+)
+
+// UniqueSession enforces the policy on every bind of a frontend session,
+// the sessions on the other frontends are found through the binding storage
+type UniqueSession struct {
+	policy         UniquePolicy
+	bindingStorage *BindingStorage
+	client         client.Client
+	hook           *session.HookHandle
+}
+
+// NewUniqueSession returns the module enforcing policy, c is used to kick
+// the sessions bound on other frontends
+func NewUniqueSession(policy UniquePolicy, bs *BindingStorage, c client.Client) *UniqueSession {
+	return &UniqueSession{
+		policy:         policy,
+		bindingStorage: bs,
+		client:         c,
+	}
+}
+
+// Init starts checking the binds
+func (u *UniqueSession) Init() error {
+	if u.bindingStorage == nil {
+		return constants.ErrNoBindingStorageModule
+	}
+	u.hook = session.OnSessionBind(u.onSessionBind)
+	return nil
+}
+
+// AfterInit is called after the module is initialized
+func (u *UniqueSession) AfterInit() {}
+
+// BeforeShutdown is called before the module is shut down
+func (u *UniqueSession) BeforeShutdown() {}
+
+// Shutdown stops checking the binds
+func (u *UniqueSession) Shutdown() error {
+	if u.hook != nil {
+		u.hook.Remove()
+	}
+	return nil
+}
+
+func (u *UniqueSession) onSessionBind(ctx context.Context, s *session.Session) error {
+	// backend binds run again on the frontend once pushed to it
+	if !s.IsFrontend || u.policy == UniqueAllow {
+		return nil
+	}
+	uid := s.UID()
+	if old := session.GetSessionByUID(uid); old != nil && old != s {
+		if u.policy == UniqueRejectNew {
+			return constants.ErrUIDAlreadyOnline
+		}
+		logger.Debugf("Kicking session of uid %s bound elsewhere, ID=%d", uid, old.ID())
+		old.SetCloseReason(session.CloseReasonLoginElsewhere)
+		if err := old.Kick(ctx); err != nil {
+			// the client may not get the kick, its session is closed anyway
+			logger.Errorf("Failed to kick session of uid %s: %s", uid, err.Error())
+			old.Close()
+		}
+		return nil
+	}
+
+	frontendID, err := u.bindingStorage.GetUserFrontendID(uid)
+	if err == constants.ErrBindingNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if frontendID == u.bindingStorage.frontendID {
+		return nil
+	}
+	if u.policy == UniqueRejectNew {
+		return constants.ErrUIDAlreadyOnline
+	}
+
+	logger.Debugf("Kicking uid %s bound on frontend %s", uid, frontendID)
+	// a uid the frontend does not find is already gone
+	if _, err := fanout.KickUsers(ctx, u.client, session.CloseReasonLoginElsewhere)(frontendID, []string{uid}); err != nil {
+		logger.Errorf("Failed to kick uid %s on frontend %s: %s", uid, frontendID, err.Error())
+		return err
+	}
+	return nil
+}
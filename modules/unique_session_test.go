@@ -0,0 +1,117 @@
+package modules
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/micro/go-micro/v2/client"
+	"github.com/micro/go-micro/v2/store/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wolfplus2048/mcbeam-plus/constants"
+	"github.com/wolfplus2048/mcbeam-plus/protos"
+	"github.com/wolfplus2048/mcbeam-plus/session"
+)
+
+// kickClient answers the KickUsers calls to other frontends
+type kickClient struct {
+	client.Client
+	kicked []string
+	err    error
+}
+
+func (c *kickClient) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	if c.err != nil {
+		return c.err
+	}
+	c.kicked = append(c.kicked, req.Body().(*proto_mcbeam.KickUsersMsg).GetUids()...)
+	return nil
+}
+
+func (c *kickClient) NewRequest(service, endpoint string, req interface{}, opts ...client.RequestOption) client.Request {
+	return client.NewClient().NewRequest(service, endpoint, req, opts...)
+}
+
+func newUniqueSession(t *testing.T, policy UniquePolicy, b *BindingStorage, c client.Client) {
+	u := NewUniqueSession(policy, b, c)
+	require.NoError(t, u.Init())
+	t.Cleanup(func() { u.Shutdown() })
+}
+
+func TestUniqueSessionLocal(t *testing.T) {
+	tables := []struct {
+		name    string
+		policy  UniquePolicy
+		kickErr error
+		err     error
+		kicked  bool
+	}{
+		{"allow", UniqueAllow, nil, nil, false},
+		{"kick old", UniqueKickOld, nil, nil, true},
+		{"kick old failing", UniqueKickOld, errors.New("broken pipe"), nil, true},
+		{"reject new", UniqueRejectNew, nil, constants.ErrUIDAlreadyOnline, false},
+	}
+	for i, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			b := newBindingStorage(t, memory.NewStore(), "1", 0)
+			newUniqueSession(t, table.policy, b, &kickClient{})
+			uid := fmt.Sprintf("local-%d", i)
+			old := &entity{kickErr: table.kickErr}
+			bound := session.New(old, true)
+			require.NoError(t, bound.Bind(context.Background(), uid))
+			t.Cleanup(func() { bound.Close() })
+
+			s := session.New(&entity{}, true)
+			t.Cleanup(func() { s.Close() })
+			assert.Equal(t, table.err, s.Bind(context.Background(), uid))
+			assert.Equal(t, table.kicked, old.kicked)
+			if table.kicked {
+				// the old session is closed even when the client missed the kick
+				assert.Equal(t, s, session.GetSessionByUID(uid))
+			}
+		})
+	}
+}
+
+func TestUniqueSessionRemote(t *testing.T) {
+	unavailable := errors.New("unavailable")
+	tables := []struct {
+		name   string
+		policy UniquePolicy
+		rpc    error
+		err    error
+		kicked []string
+	}{
+		{"allow", UniqueAllow, nil, nil, nil},
+		{"kick old", UniqueKickOld, nil, nil, []string{"remote"}},
+		{"kick old unreachable", UniqueKickOld, unavailable, unavailable, nil},
+		{"reject new", UniqueRejectNew, nil, constants.ErrUIDAlreadyOnline, nil},
+	}
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			st := memory.NewStore()
+			b := newBindingStorage(t, st, "1", 0)
+			c := &kickClient{err: table.rpc}
+			newUniqueSession(t, table.policy, b, c)
+			other := NewBindingStorage(st, nil, 0)
+			other.frontendID = "gate-2"
+			require.NoError(t, other.PutBinding("remote"))
+
+			s := session.New(&entity{}, true)
+			t.Cleanup(func() { s.Close() })
+			assert.Equal(t, table.err, s.Bind(context.Background(), "remote"))
+			assert.Equal(t, table.kicked, c.kicked)
+
+			// a failed bind leaves the binding of the other frontend
+			fid, err := b.GetUserFrontendID("remote")
+			assert.NoError(t, err)
+			if table.err != nil {
+				assert.Equal(t, "gate-2", fid)
+			} else {
+				assert.Equal(t, "gate-1", fid)
+			}
+		})
+	}
+}
@@ -1,13 +1,18 @@
 package mcbeam
 
 import (
+	"time"
+
 	"github.com/micro/go-micro/v2"
 	"github.com/micro/go-micro/v2/broker"
 	"github.com/micro/go-micro/v2/client"
 	"github.com/micro/go-micro/v2/registry"
 	"github.com/micro/go-micro/v2/server"
 	"github.com/micro/go-micro/v2/store"
+	"github.com/wolfplus2048/mcbeam-plus/gateway"
+	"github.com/wolfplus2048/mcbeam-plus/group"
 	"github.com/wolfplus2048/mcbeam-plus/mcb_handler"
+	"github.com/wolfplus2048/mcbeam-plus/modules"
 	"github.com/wolfplus2048/mcbeam-plus/scheduler"
 )
 
@@ -18,17 +23,31 @@ type Options struct {
 	Registry      registry.Registry
 	Broker        broker.Broker
 	Store         store.Store
+	BindingLease  time.Duration
 	Scheduler     scheduler.Scheduler
 	McbAppHandler mcb_handler.McbAppHandler
+	Gateway       gateway.Gateway
 	Concurrency   bool
+	UniqueSession modules.UniquePolicy
+	Admin         bool
+	AdminAddress  string
+	AdminToken    string
+	GroupService  group.Service
+	GroupLease    time.Duration
+	// pushes are sent on their own unless PushFlushWindow is set
+	PushFlushWindow time.Duration
+	PushBatchSize   int
+	// McbHdlrWrappers are set on McbAppHandler by Init
+	McbHdlrWrappers []mcb_handler.HandlerWrapper
 }
 type Option func(o *Options)
 
 func newOptions(opt ...Option) Options {
 	opts := Options{
-		Service:     micro.NewService(),
-		Scheduler:   scheduler.Default,
-		Concurrency: false,
+		Service:       micro.NewService(),
+		Scheduler:     scheduler.Default,
+		McbAppHandler: mcb_handler.NewMcbServer(),
+		Concurrency:   false,
 	}
 	for _, o := range opt {
 		o(&opts)
@@ -77,6 +96,76 @@ func Store(s store.Store) Option {
 		o.Store = s
 	}
 }
+
+// BindingLease sets how long the bindings of the uids to the frontend
+// stay in the Store once the frontend stopped renewing them, zero uses
+// modules.DefaultBindingLease
+func BindingLease(lease time.Duration) Option {
+	return func(o *Options) {
+		o.BindingLease = lease
+	}
+}
+
+// Gateway makes the service a frontend accepting client connections
+func Gateway(g gateway.Gateway) Option {
+	return func(o *Options) {
+		o.Gateway = g
+	}
+}
+
+// UniqueSession sets what happens when a uid binds while it is bound to
+// another session, on any frontend. It needs a Store.
+func UniqueSession(p modules.UniquePolicy) Option {
+	return func(o *Options) {
+		o.UniqueSession = p
+	}
+}
+
+// Admin serves the McbAdmin rpc to query and kick sessions, and its http
+// json counterpart on addr unless it is empty. Without AdminToken the http
+// endpoint only listens on loopback.
+func Admin(addr string) Option {
+	return func(o *Options) {
+		o.Admin = true
+		o.AdminAddress = addr
+	}
+}
+
+// AdminToken is the bearer token the admin http requests must carry
+func AdminToken(token string) Option {
+	return func(o *Options) {
+		o.AdminToken = token
+	}
+}
+
+// GroupService keeps the groups of the service in s, see Groups
+func GroupService(s group.Service) Option {
+	return func(o *Options) {
+		o.GroupService = s
+	}
+}
+
+// DistributedGroups keeps the groups in the Store, shared by the cluster.
+// The members expire lease after the server that added them stopped, zero
+// uses group.DefaultMemberLease.
+func DistributedGroups(lease time.Duration) Option {
+	return func(o *Options) {
+		if lease <= 0 {
+			lease = group.DefaultMemberLease
+		}
+		o.GroupLease = lease
+	}
+}
+
+// PushBatching buffers the pushes of the backend to a frontend for window
+// and sends up to size of them in one rpc. Session.Push then returns
+// before the push is sent, use Session.PushContext to get its error.
+func PushBatching(window time.Duration, size int) Option {
+	return func(o *Options) {
+		o.PushFlushWindow = window
+		o.PushBatchSize = size
+	}
+}
 func Scheduler(s scheduler.Scheduler) Option {
 	return func(o *Options) {
 		o.Scheduler = s
@@ -124,6 +213,14 @@ func WrapHandler(w ...server.HandlerWrapper) Option {
 	}
 }
 
+// WrapMcbHandler adds wrappers around the component methods called for
+// client messages, the first one is the outermost
+func WrapMcbHandler(w ...mcb_handler.HandlerWrapper) Option {
+	return func(o *Options) {
+		o.McbHdlrWrappers = append(o.McbHdlrWrappers, w...)
+	}
+}
+
 // WrapSubscriber adds a subscriber Wrapper to a list of options passed into the server
 func WrapSubscriber(w ...server.SubscriberWrapper) Option {
 	return func(o *Options) {
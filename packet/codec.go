@@ -0,0 +1,159 @@
+// Copyright (c) nano Author and wolfplus. All Rights Reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package packet
+
+import "bytes"
+
+// Encoder interface
+type Encoder interface {
+	Encode(typ Type, data []byte) ([]byte, error)
+}
+
+// Decoder interface
+type Decoder interface {
+	Decode(data []byte) ([]*Packet, error)
+}
+
+// PomeloEncoder encodes packets with the pomelo protocol header
+type PomeloEncoder struct{}
+
+// NewPomeloEncoder returns a new packet encoder
+func NewPomeloEncoder() *PomeloEncoder {
+	return &PomeloEncoder{}
+}
+
+// Encode create a packet.Packet from  the raw bytes slice and then encode to network bytes slice
+// Protocol refs: https://github.com/NetEase/pomelo/wiki/Communication-Protocol
+//
+// -<type>-|--------<length>--------|-<data>-
+// --------|------------------------|--------
+// 1 byte packet type, 3 bytes packet data length(big end), and data segment
+func (e *PomeloEncoder) Encode(typ Type, data []byte) ([]byte, error) {
+	return Encode(typ, data)
+}
+
+// Encode is the stateless version of PomeloEncoder.Encode
+func Encode(typ Type, data []byte) ([]byte, error) {
+	if invalidType(typ) {
+		return nil, ErrWrongPacketType
+	}
+	if len(data) > MaxPacketSize {
+		return nil, ErrPacketSizeExceed
+	}
+
+	buf := make([]byte, len(data)+HeadLength)
+	buf[0] = byte(typ)
+
+	copy(buf[1:HeadLength], intToBytes(len(data)))
+	copy(buf[HeadLength:], data)
+
+	return buf, nil
+}
+
+// PomeloDecoder reads and decodes network data slice. It keeps the bytes
+// of a partially received packet, so it must not be shared between
+// connections.
+type PomeloDecoder struct {
+	buf  *bytes.Buffer
+	size int  // last packet length
+	typ  byte // last packet type
+}
+
+// NewPomeloDecoder returns a new streaming packet decoder
+func NewPomeloDecoder() *PomeloDecoder {
+	return &PomeloDecoder{
+		buf:  bytes.NewBuffer(nil),
+		size: -1,
+	}
+}
+
+func (c *PomeloDecoder) forward() error {
+	header := c.buf.Next(HeadLength)
+	c.typ = header[0]
+	if invalidType(Type(c.typ)) {
+		return ErrWrongPacketType
+	}
+	c.size = bytesToInt(header[1:])
+
+	// packet length limitation
+	if c.size > MaxPacketSize {
+		return ErrPacketSizeExceed
+	}
+	return nil
+}
+
+// Decode decodes the network bytes slice to packet.Packet(s). Trailing
+// bytes of an incomplete packet are kept until the next call.
+func (c *PomeloDecoder) Decode(data []byte) ([]*Packet, error) {
+	c.buf.Write(data)
+
+	var (
+		packets []*Packet
+		err     error
+	)
+	// check length
+	if c.buf.Len() < HeadLength {
+		return nil, err
+	}
+
+	// first time
+	if c.size < 0 {
+		if err = c.forward(); err != nil {
+			return nil, err
+		}
+	}
+
+	for c.size <= c.buf.Len() {
+		p := &Packet{Type: Type(c.typ), Length: c.size, Data: make([]byte, c.size)}
+		copy(p.Data, c.buf.Next(c.size))
+		packets = append(packets, p)
+
+		// more packet
+		if c.buf.Len() < HeadLength {
+			c.size = -1
+			break
+		}
+
+		if err = c.forward(); err != nil {
+			return packets, err
+		}
+	}
+
+	return packets, nil
+}
+
+// Decode packet data length byte to int(Big end)
+func bytesToInt(b []byte) int {
+	result := 0
+	for _, v := range b {
+		result = result<<8 + int(v)
+	}
+	return result
+}
+
+// Encode packet data length to bytes(Big end)
+func intToBytes(n int) []byte {
+	buf := make([]byte, 3)
+	buf[0] = byte((n >> 16) & 0xFF)
+	buf[1] = byte((n >> 8) & 0xFF)
+	buf[2] = byte(n & 0xFF)
+	return buf
+}
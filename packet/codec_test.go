@@ -0,0 +1,69 @@
+package packet
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncode(t *testing.T) {
+	t.Parallel()
+	tables := []struct {
+		name string
+		typ  Type
+		data []byte
+		out  []byte
+		err  error
+	}{
+		{"heartbeat", Heartbeat, nil, []byte{Heartbeat, 0x00, 0x00, 0x00}, nil},
+		{"data", Data, []byte{0x01, 0x02}, []byte{Data, 0x00, 0x00, 0x02, 0x01, 0x02}, nil},
+		{"invalid_type", Type(0xFF), nil, nil, ErrWrongPacketType},
+		{"too_big", Data, make([]byte, MaxPacketSize+1), nil, ErrPacketSizeExceed},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			b, err := Encode(table.typ, table.data)
+			assert.Equal(t, table.err, err)
+			assert.Equal(t, table.out, b)
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	t.Parallel()
+	hb, _ := Encode(Heartbeat, nil)
+	data, _ := Encode(Data, []byte("hello"))
+
+	d := NewPomeloDecoder()
+	packets, err := d.Decode(append(hb, data...))
+	assert.NoError(t, err)
+	assert.Len(t, packets, 2)
+	assert.Equal(t, Type(Heartbeat), packets[0].Type)
+	assert.Equal(t, Type(Data), packets[1].Type)
+	assert.Equal(t, []byte("hello"), packets[1].Data)
+}
+
+func TestDecodeStream(t *testing.T) {
+	t.Parallel()
+	data, _ := Encode(Data, []byte("hello"))
+
+	d := NewPomeloDecoder()
+	for i := 0; i < len(data)-1; i++ {
+		packets, err := d.Decode(data[i : i+1])
+		assert.NoError(t, err)
+		assert.Empty(t, packets)
+	}
+	packets, err := d.Decode(data[len(data)-1:])
+	assert.NoError(t, err)
+	assert.Len(t, packets, 1)
+	assert.Equal(t, []byte("hello"), packets[0].Data)
+	assert.Equal(t, 5, packets[0].Length)
+}
+
+func TestDecodeInvalidType(t *testing.T) {
+	t.Parallel()
+	d := NewPomeloDecoder()
+	_, err := d.Decode([]byte{0x09, 0x00, 0x00, 0x00})
+	assert.Equal(t, ErrWrongPacketType, err)
+}
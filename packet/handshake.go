@@ -0,0 +1,51 @@
+package packet
+
+import "encoding/json"
+
+// Handshake response codes understood by pomelo/starx clients
+const (
+	HandshakeOK        = 200
+	HandshakeFail      = 500
+	HandshakeOldClient = 501
+)
+
+// HandshakeSys is the framework part of the handshake response
+type HandshakeSys struct {
+	Heartbeat  int               `json:"heartbeat,omitempty"`
+	Dict       map[string]uint16 `json:"dict,omitempty"`
+	Serializer string            `json:"serializer,omitempty"`
+	// Protos is the protobuf schema of the routes, omitted when the client
+	// already has the current version
+	Protos json.RawMessage `json:"protos,omitempty"`
+	// Resumed tells the client it got its previous session back
+	Resumed bool `json:"resumed,omitempty"`
+}
+
+// HandshakeResponse is sent by the server in reply to a client handshake
+type HandshakeResponse struct {
+	Code int          `json:"code"`
+	Sys  HandshakeSys `json:"sys"`
+}
+
+// KickBody is the body of a kick packet
+type KickBody struct {
+	Reason string `json:"reason,omitempty"`
+}
+
+// EncodeHandshakeResponse encodes the response into a handshake packet
+func EncodeHandshakeResponse(r *HandshakeResponse) ([]byte, error) {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+	return Encode(Handshake, data)
+}
+
+// EncodeKick encodes a kick packet carrying the reason of the disconnection
+func EncodeKick(reason string) ([]byte, error) {
+	data, err := json.Marshal(&KickBody{Reason: reason})
+	if err != nil {
+		return nil, err
+	}
+	return Encode(Kick, data)
+}
@@ -0,0 +1,91 @@
+// Copyright (c) nano Author and wolfplus. All Rights Reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package packet
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Type represents the network packet's type such as: handshake and so on.
+type Type byte
+
+const (
+	_ Type = iota
+	// Handshake represents a handshake: request(client) <====> handshake response(server)
+	Handshake = 0x01
+
+	// HandshakeAck represents a handshake ack from client to server
+	HandshakeAck = 0x02
+
+	// Heartbeat represents a heartbeat
+	Heartbeat = 0x03
+
+	// Data represents a common data packet
+	Data = 0x04
+
+	// Kick represents a kick off packet
+	Kick = 0x05 // disconnect message from server
+)
+
+const (
+	// HeadLength is the packet header length: 1 byte type and 3 bytes body length
+	HeadLength = 4
+	// MaxPacketSize is the max size of a packet body
+	MaxPacketSize = 1<<24 - 1
+)
+
+// Errors that could be occurred in packet codec
+var (
+	// ErrWrongPacketType represents a wrong packet type.
+	ErrWrongPacketType = errors.New("wrong packet type")
+	// ErrPacketSizeExceed is the error used for encode/decode.
+	ErrPacketSizeExceed = errors.New("codec: packet size exceed")
+)
+
+var types = map[Type]string{
+	Handshake:    "Handshake",
+	HandshakeAck: "HandshakeAck",
+	Heartbeat:    "Heartbeat",
+	Data:         "Data",
+	Kick:         "Kick",
+}
+
+// Packet represents a network packet.
+type Packet struct {
+	Type   Type
+	Length int
+	Data   []byte
+}
+
+// New create a Packet instance.
+func New() *Packet {
+	return &Packet{}
+}
+
+// String represents the Packet's in text mode.
+func (p *Packet) String() string {
+	return fmt.Sprintf("Type: %s, Length: %d, Data: %s", types[p.Type], p.Length, string(p.Data))
+}
+
+func invalidType(t Type) bool {
+	return t < Handshake || t > Kick
+}
@@ -105,6 +105,12 @@ type McbGateService interface {
 	PushSession(ctx context.Context, in *Session, opts ...client.CallOption) (*Response, error)
 	Bind(ctx context.Context, in *Session, opts ...client.CallOption) (*Response, error)
 	Kick(ctx context.Context, in *KickMsg, opts ...client.CallOption) (*KickAnswer, error)
+	ResponseMID(ctx context.Context, in *ResponseMsg, opts ...client.CallOption) (*Response, error)
+	PushToUsers(ctx context.Context, in *PushToUsersMsg, opts ...client.CallOption) (*UsersAnswer, error)
+	KickUsers(ctx context.Context, in *KickUsersMsg, opts ...client.CallOption) (*UsersAnswer, error)
+	PushSessionDelta(ctx context.Context, in *SessionDelta, opts ...client.CallOption) (*SessionDeltaAnswer, error)
+	GetSession(ctx context.Context, in *Session, opts ...client.CallOption) (*Session, error)
+	PushBatch(ctx context.Context, in *PushBatchMsg, opts ...client.CallOption) (*UsersAnswer, error)
 }
 
 type mcbGateService struct {
@@ -159,6 +165,66 @@ func (c *mcbGateService) Kick(ctx context.Context, in *KickMsg, opts ...client.C
 	return out, nil
 }
 
+func (c *mcbGateService) ResponseMID(ctx context.Context, in *ResponseMsg, opts ...client.CallOption) (*Response, error) {
+	req := c.c.NewRequest(c.name, "McbGate.ResponseMID", in)
+	out := new(Response)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mcbGateService) PushToUsers(ctx context.Context, in *PushToUsersMsg, opts ...client.CallOption) (*UsersAnswer, error) {
+	req := c.c.NewRequest(c.name, "McbGate.PushToUsers", in)
+	out := new(UsersAnswer)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mcbGateService) KickUsers(ctx context.Context, in *KickUsersMsg, opts ...client.CallOption) (*UsersAnswer, error) {
+	req := c.c.NewRequest(c.name, "McbGate.KickUsers", in)
+	out := new(UsersAnswer)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mcbGateService) PushSessionDelta(ctx context.Context, in *SessionDelta, opts ...client.CallOption) (*SessionDeltaAnswer, error) {
+	req := c.c.NewRequest(c.name, "McbGate.PushSessionDelta", in)
+	out := new(SessionDeltaAnswer)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mcbGateService) GetSession(ctx context.Context, in *Session, opts ...client.CallOption) (*Session, error) {
+	req := c.c.NewRequest(c.name, "McbGate.GetSession", in)
+	out := new(Session)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mcbGateService) PushBatch(ctx context.Context, in *PushBatchMsg, opts ...client.CallOption) (*UsersAnswer, error) {
+	req := c.c.NewRequest(c.name, "McbGate.PushBatch", in)
+	out := new(UsersAnswer)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for McbGate service
 
 type McbGateHandler interface {
@@ -166,6 +232,12 @@ type McbGateHandler interface {
 	PushSession(context.Context, *Session, *Response) error
 	Bind(context.Context, *Session, *Response) error
 	Kick(context.Context, *KickMsg, *KickAnswer) error
+	ResponseMID(context.Context, *ResponseMsg, *Response) error
+	PushToUsers(context.Context, *PushToUsersMsg, *UsersAnswer) error
+	KickUsers(context.Context, *KickUsersMsg, *UsersAnswer) error
+	PushSessionDelta(context.Context, *SessionDelta, *SessionDeltaAnswer) error
+	GetSession(context.Context, *Session, *Session) error
+	PushBatch(context.Context, *PushBatchMsg, *UsersAnswer) error
 }
 
 func RegisterMcbGateHandler(s server.Server, hdlr McbGateHandler, opts ...server.HandlerOption) error {
@@ -174,6 +246,12 @@ func RegisterMcbGateHandler(s server.Server, hdlr McbGateHandler, opts ...server
 		PushSession(ctx context.Context, in *Session, out *Response) error
 		Bind(ctx context.Context, in *Session, out *Response) error
 		Kick(ctx context.Context, in *KickMsg, out *KickAnswer) error
+		ResponseMID(ctx context.Context, in *ResponseMsg, out *Response) error
+		PushToUsers(ctx context.Context, in *PushToUsersMsg, out *UsersAnswer) error
+		KickUsers(ctx context.Context, in *KickUsersMsg, out *UsersAnswer) error
+		PushSessionDelta(ctx context.Context, in *SessionDelta, out *SessionDeltaAnswer) error
+		GetSession(ctx context.Context, in *Session, out *Session) error
+		PushBatch(ctx context.Context, in *PushBatchMsg, out *UsersAnswer) error
 	}
 	type McbGate struct {
 		mcbGate
@@ -201,3 +279,137 @@ func (h *mcbGateHandler) Bind(ctx context.Context, in *Session, out *Response) e
 func (h *mcbGateHandler) Kick(ctx context.Context, in *KickMsg, out *KickAnswer) error {
 	return h.McbGateHandler.Kick(ctx, in, out)
 }
+
+func (h *mcbGateHandler) ResponseMID(ctx context.Context, in *ResponseMsg, out *Response) error {
+	return h.McbGateHandler.ResponseMID(ctx, in, out)
+}
+
+func (h *mcbGateHandler) PushToUsers(ctx context.Context, in *PushToUsersMsg, out *UsersAnswer) error {
+	return h.McbGateHandler.PushToUsers(ctx, in, out)
+}
+
+func (h *mcbGateHandler) KickUsers(ctx context.Context, in *KickUsersMsg, out *UsersAnswer) error {
+	return h.McbGateHandler.KickUsers(ctx, in, out)
+}
+
+func (h *mcbGateHandler) PushSessionDelta(ctx context.Context, in *SessionDelta, out *SessionDeltaAnswer) error {
+	return h.McbGateHandler.PushSessionDelta(ctx, in, out)
+}
+
+func (h *mcbGateHandler) GetSession(ctx context.Context, in *Session, out *Session) error {
+	return h.McbGateHandler.GetSession(ctx, in, out)
+}
+
+// Api Endpoints for McbAdmin service
+
+func NewMcbAdminEndpoints() []*api.Endpoint {
+	return []*api.Endpoint{}
+}
+
+// Client API for McbAdmin service
+
+type McbAdminService interface {
+	ListSessions(ctx context.Context, in *SessionFilter, opts ...client.CallOption) (*SessionList, error)
+	CountSessions(ctx context.Context, in *SessionFilter, opts ...client.CallOption) (*SessionCount, error)
+	GetSession(ctx context.Context, in *SessionQuery, opts ...client.CallOption) (*SessionInfo, error)
+	KickSession(ctx context.Context, in *SessionQuery, opts ...client.CallOption) (*KickAnswer, error)
+}
+
+type mcbAdminService struct {
+	c    client.Client
+	name string
+}
+
+func NewMcbAdminService(name string, c client.Client) McbAdminService {
+	return &mcbAdminService{
+		c:    c,
+		name: name,
+	}
+}
+
+func (c *mcbAdminService) ListSessions(ctx context.Context, in *SessionFilter, opts ...client.CallOption) (*SessionList, error) {
+	req := c.c.NewRequest(c.name, "McbAdmin.ListSessions", in)
+	out := new(SessionList)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mcbAdminService) CountSessions(ctx context.Context, in *SessionFilter, opts ...client.CallOption) (*SessionCount, error) {
+	req := c.c.NewRequest(c.name, "McbAdmin.CountSessions", in)
+	out := new(SessionCount)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mcbAdminService) GetSession(ctx context.Context, in *SessionQuery, opts ...client.CallOption) (*SessionInfo, error) {
+	req := c.c.NewRequest(c.name, "McbAdmin.GetSession", in)
+	out := new(SessionInfo)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mcbAdminService) KickSession(ctx context.Context, in *SessionQuery, opts ...client.CallOption) (*KickAnswer, error) {
+	req := c.c.NewRequest(c.name, "McbAdmin.KickSession", in)
+	out := new(KickAnswer)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for McbAdmin service
+
+type McbAdminHandler interface {
+	ListSessions(context.Context, *SessionFilter, *SessionList) error
+	CountSessions(context.Context, *SessionFilter, *SessionCount) error
+	GetSession(context.Context, *SessionQuery, *SessionInfo) error
+	KickSession(context.Context, *SessionQuery, *KickAnswer) error
+}
+
+func RegisterMcbAdminHandler(s server.Server, hdlr McbAdminHandler, opts ...server.HandlerOption) error {
+	type mcbAdmin interface {
+		ListSessions(ctx context.Context, in *SessionFilter, out *SessionList) error
+		CountSessions(ctx context.Context, in *SessionFilter, out *SessionCount) error
+		GetSession(ctx context.Context, in *SessionQuery, out *SessionInfo) error
+		KickSession(ctx context.Context, in *SessionQuery, out *KickAnswer) error
+	}
+	type McbAdmin struct {
+		mcbAdmin
+	}
+	h := &mcbAdminHandler{hdlr}
+	return s.Handle(s.NewHandler(&McbAdmin{h}, opts...))
+}
+
+type mcbAdminHandler struct {
+	McbAdminHandler
+}
+
+func (h *mcbAdminHandler) ListSessions(ctx context.Context, in *SessionFilter, out *SessionList) error {
+	return h.McbAdminHandler.ListSessions(ctx, in, out)
+}
+
+func (h *mcbAdminHandler) CountSessions(ctx context.Context, in *SessionFilter, out *SessionCount) error {
+	return h.McbAdminHandler.CountSessions(ctx, in, out)
+}
+
+func (h *mcbAdminHandler) GetSession(ctx context.Context, in *SessionQuery, out *SessionInfo) error {
+	return h.McbAdminHandler.GetSession(ctx, in, out)
+}
+
+func (h *mcbAdminHandler) KickSession(ctx context.Context, in *SessionQuery, out *KickAnswer) error {
+	return h.McbAdminHandler.KickSession(ctx, in, out)
+}
+
+func (h *mcbGateHandler) PushBatch(ctx context.Context, in *PushBatchMsg, out *UsersAnswer) error {
+	return h.McbGateHandler.PushBatch(ctx, in, out)
+}
@@ -0,0 +1,85 @@
+package mcbeam
+
+import (
+	"context"
+
+	"github.com/micro/go-micro/v2/logger"
+	"github.com/wolfplus2048/mcbeam-plus/constants"
+	"github.com/wolfplus2048/mcbeam-plus/fanout"
+	"github.com/wolfplus2048/mcbeam-plus/group"
+	"github.com/wolfplus2048/mcbeam-plus/protos"
+	"github.com/wolfplus2048/mcbeam-plus/serialize/protobuf"
+	"github.com/wolfplus2048/mcbeam-plus/util"
+)
+
+// PushToUser pushes the message to the user bound to uid, whatever frontend
+// the user is connected to. The service needs a Store to find the frontend.
+func PushToUser(ctx context.Context, uid, route string, v interface{}) error {
+	if app == nil || app.bindingStorage == nil {
+		return constants.ErrNoBindingStorageModule
+	}
+	frontendID, err := app.bindingStorage.GetUserFrontendID(uid)
+	if err != nil {
+		return err
+	}
+	data, err := util.SerializeOrRaw(protobuf.NewSerializer(), v)
+	if err != nil {
+		return err
+	}
+
+	logger.Debugf("Type=PushToUser, UID=%s, Route=%s, Frontend=%s", uid, route, frontendID)
+	push := &proto_mcbeam.PushMsg{
+		Route: route,
+		Uid:   uid,
+		Data:  data,
+	}
+	_, err = fanout.Gate(app.Client(), frontendID).Push(ctx, push, fanout.Select(frontendID))
+	return err
+}
+
+// Groups returns the groups of the service set with the GroupService
+// option, nil without it
+func Groups() *group.Groups {
+	if app == nil {
+		return nil
+	}
+	return app.groups
+}
+
+// SendPushToUsers pushes the message to the users, sending one rpc to each
+// frontend they are bound on. It returns the uids the push failed for.
+// Without a Store the users are looked for on every frontend of
+// frontendType.
+func SendPushToUsers(ctx context.Context, route string, v interface{}, uids []string, frontendType string) ([]string, error) {
+	if app == nil {
+		return uids, constants.ErrNoBindingStorageModule
+	}
+	data, err := util.SerializeOrRaw(protobuf.NewSerializer(), v)
+	if err != nil {
+		return uids, err
+	}
+
+	logger.Debugf("Type=SendPushToUsers, Route=%s, UIDs=%v", route, uids)
+	failed, err := sendToUsers(uids, frontendType, fanout.PushToUsers(ctx, app.Client(), route, data))
+	if err != nil {
+		return failed, err
+	}
+	if len(failed) > 0 {
+		return failed, constants.ErrPushingToUsers
+	}
+	return nil, nil
+}
+
+// bindings returns the binding storage of the service, nil without it
+func bindings() fanout.Bindings {
+	if app.bindingStorage == nil {
+		return nil
+	}
+	return app.bindingStorage
+}
+
+// sendToUsers sends to the users with one rpc to each frontend they are
+// bound on, see fanout.ToUsers
+func sendToUsers(uids []string, frontendType string, send fanout.Send) ([]string, error) {
+	return fanout.ToUsers(bindings(), app.opts.Service.Options().Registry, frontendType, uids, send)
+}
@@ -0,0 +1,79 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// Limit is a token bucket configuration, Rate tokens are added each second
+// up to Burst tokens
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Bucket is a token bucket, each allowed event takes one token
+type Bucket struct {
+	mu     sync.Mutex
+	limit  Limit
+	tokens float64
+	last   time.Time
+}
+
+// NewBucket returns a full bucket
+func NewBucket(l Limit) *Bucket {
+	return &Bucket{
+		limit:  l,
+		tokens: float64(l.Burst),
+		last:   time.Now(),
+	}
+}
+
+// Allow takes a token from the bucket, it returns false when it is empty
+func (b *Bucket) Allow() bool {
+	return b.AllowAt(time.Now())
+}
+
+// AllowAt is Allow as if it was called at now
+func (b *Bucket) AllowAt(now time.Time) bool {
+	return AllowAllAt(now, b)
+}
+
+// AllowAll takes a token from each bucket when none of them is empty, an
+// event rejected by one bucket does not use the tokens of the others. Nil
+// buckets are skipped, callers sharing buckets must pass them in the same
+// order.
+func AllowAll(buckets ...*Bucket) bool {
+	return AllowAllAt(time.Now(), buckets...)
+}
+
+// AllowAllAt is AllowAll as if it was called at now
+func AllowAllAt(now time.Time, buckets ...*Bucket) bool {
+	for _, b := range buckets {
+		if b == nil {
+			continue
+		}
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.refill(now)
+		if b.tokens < 1 {
+			return false
+		}
+	}
+	for _, b := range buckets {
+		if b != nil {
+			b.tokens--
+		}
+	}
+	return true
+}
+
+func (b *Bucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens += elapsed.Seconds() * b.limit.Rate
+		if b.tokens > float64(b.limit.Burst) {
+			b.tokens = float64(b.limit.Burst)
+		}
+		b.last = now
+	}
+}
@@ -0,0 +1,43 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBucketBurst(t *testing.T) {
+	b := NewBucket(Limit{Rate: 2, Burst: 3})
+	now := b.last
+	for i := 0; i < 3; i++ {
+		assert.True(t, b.AllowAt(now))
+	}
+	assert.False(t, b.AllowAt(now))
+}
+
+func TestBucketRefill(t *testing.T) {
+	b := NewBucket(Limit{Rate: 2, Burst: 1})
+	now := b.last
+	assert.True(t, b.AllowAt(now))
+	assert.False(t, b.AllowAt(now.Add(100*time.Millisecond)))
+	assert.True(t, b.AllowAt(now.Add(500*time.Millisecond)))
+	assert.False(t, b.AllowAt(now.Add(600*time.Millisecond)))
+
+	// tokens never go over the burst
+	later := now.Add(time.Minute)
+	assert.True(t, b.AllowAt(later))
+	assert.False(t, b.AllowAt(later))
+}
+
+func TestAllowAllKeepsTokens(t *testing.T) {
+	session := NewBucket(Limit{Rate: 1, Burst: 2})
+	route := NewBucket(Limit{Rate: 1, Burst: 1})
+	now := session.last
+
+	assert.True(t, AllowAllAt(now, session, route))
+	// the route bucket is empty, the session token is not taken
+	assert.False(t, AllowAllAt(now, session, route))
+	assert.True(t, AllowAllAt(now, session, nil))
+	assert.False(t, AllowAllAt(now, session, nil))
+}
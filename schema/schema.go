@@ -0,0 +1,215 @@
+package schema
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	protov2 "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// Route describes the messages a client route takes and returns by their
+// protobuf full names, an empty name means the payload is not protobuf
+type Route struct {
+	Request  string `json:"request,omitempty"`
+	Response string `json:"response,omitempty"`
+}
+
+// Schema holds what clients need to encode and decode the routes payloads
+type Schema struct {
+	// Version is a hash of the routes and descriptors, clients caching the
+	// schema send it back on handshake to avoid downloading it again
+	Version string            `json:"version"`
+	Routes  map[string]*Route `json:"routes"`
+	// Descriptors is a serialized google.protobuf.FileDescriptorSet with the
+	// files declaring the routes messages and their imports
+	Descriptors []byte `json:"descriptors,omitempty"`
+}
+
+var (
+	typeOfProtoMsg = reflect.TypeOf((*proto.Message)(nil)).Elem()
+
+	mu     sync.Mutex
+	routes = make(map[string]*Route)
+	files  = make(map[string][]protoreflect.FileDescriptor) // route -> files of its messages
+	cached *Schema
+)
+
+// Register records the request and response types of a client route.
+// Types that are not protobuf messages are left out of the schema.
+func Register(route string, req, rsp reflect.Type) {
+	r := &Route{}
+	reqDesc, rspDesc := descriptorOf(req), descriptorOf(rsp)
+	if reqDesc == nil && rspDesc == nil {
+		return
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	var fds []protoreflect.FileDescriptor
+	if reqDesc != nil {
+		r.Request = string(reqDesc.FullName())
+		fds = append(fds, reqDesc.ParentFile())
+	}
+	if rspDesc != nil {
+		r.Response = string(rspDesc.FullName())
+		fds = append(fds, rspDesc.ParentFile())
+	}
+	routes[route] = r
+	files[route] = fds
+	cached = nil
+}
+
+// Get returns the schema of the registered routes, it is built once and
+// cached until a new route is registered
+func Get() (*Schema, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	if cached != nil {
+		return cached, nil
+	}
+	names := make([]string, 0, len(routes))
+	for r := range routes {
+		names = append(names, r)
+	}
+	s, err := build(names)
+	if err != nil {
+		return nil, err
+	}
+	cached = s
+	return s, nil
+}
+
+// Of returns the schema of the given registered routes only, servers
+// sharing a process each publish the schema of their own routes
+func Of(rs ...string) (*Schema, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	return build(rs)
+}
+
+// Merge returns the schema with the routes and descriptors of all the
+// schemas, gateways merge the schemas published by the servers of the
+// cluster. Routes found in several schemas keep the last one.
+func Merge(schemas ...*Schema) (*Schema, error) {
+	s := &Schema{Routes: make(map[string]*Route)}
+	protos := make(map[string]*descriptorpb.FileDescriptorProto)
+	for _, sc := range schemas {
+		if sc == nil {
+			continue
+		}
+		for k, v := range sc.Routes {
+			r := *v
+			s.Routes[k] = &r
+		}
+		set := &descriptorpb.FileDescriptorSet{}
+		if err := protov2.Unmarshal(sc.Descriptors, set); err != nil {
+			return nil, err
+		}
+		for _, fd := range set.File {
+			protos[fd.GetName()] = fd
+		}
+	}
+
+	names := make([]string, 0, len(protos))
+	for n := range protos {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	// imports go before the files depending on them
+	set := &descriptorpb.FileDescriptorSet{}
+	visited := make(map[string]bool)
+	var visit func(name string)
+	visit = func(name string) {
+		fd, ok := protos[name]
+		if !ok || visited[name] {
+			return
+		}
+		visited[name] = true
+		for _, dep := range fd.GetDependency() {
+			visit(dep)
+		}
+		set.File = append(set.File, fd)
+	}
+	for _, n := range names {
+		visit(n)
+	}
+	return finish(s, set)
+}
+
+// build returns the schema of the routes, mu must be held
+func build(rs []string) (*Schema, error) {
+	s := &Schema{Routes: make(map[string]*Route, len(rs))}
+	roots := make(map[string]protoreflect.FileDescriptor)
+	for _, k := range rs {
+		v, ok := routes[k]
+		if !ok {
+			continue
+		}
+		r := *v
+		s.Routes[k] = &r
+		for _, fd := range files[k] {
+			roots[fd.Path()] = fd
+		}
+	}
+
+	paths := make([]string, 0, len(roots))
+	for p := range roots {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	// imports go before the files depending on them
+	set := &descriptorpb.FileDescriptorSet{}
+	visited := make(map[string]bool)
+	var visit func(fd protoreflect.FileDescriptor)
+	visit = func(fd protoreflect.FileDescriptor) {
+		if visited[fd.Path()] {
+			return
+		}
+		visited[fd.Path()] = true
+		imports := fd.Imports()
+		for i := 0; i < imports.Len(); i++ {
+			visit(imports.Get(i).FileDescriptor)
+		}
+		set.File = append(set.File, protodesc.ToFileDescriptorProto(fd))
+	}
+	for _, p := range paths {
+		visit(roots[p])
+	}
+	return finish(s, set)
+}
+
+// finish sets the descriptors and the version of the schema
+func finish(s *Schema, set *descriptorpb.FileDescriptorSet) (*Schema, error) {
+	descriptors, err := protov2.MarshalOptions{Deterministic: true}.Marshal(set)
+	if err != nil {
+		return nil, err
+	}
+	s.Descriptors = descriptors
+
+	// json sorts map keys, so the same routes always hash the same
+	data, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+	s.Version = hex.EncodeToString(sum[:8])
+	return s, nil
+}
+
+func descriptorOf(t reflect.Type) protoreflect.MessageDescriptor {
+	if t == nil || t.Kind() != reflect.Ptr || !t.Implements(typeOfProtoMsg) {
+		return nil
+	}
+	msg := reflect.New(t.Elem()).Interface().(proto.Message)
+	return proto.MessageReflect(msg).Descriptor()
+}
@@ -0,0 +1,54 @@
+package schema
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wolfplus2048/mcbeam-plus/protos"
+	protov2 "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestOfOnlyHasTheRoutes(t *testing.T) {
+	Register("room.table.kick", reflect.TypeOf(&proto_mcbeam.KickMsg{}), reflect.TypeOf(&proto_mcbeam.KickAnswer{}))
+	Register("chat.channel.send", reflect.TypeOf(&proto_mcbeam.PushMsg{}), nil)
+
+	s, err := Of("room.table.kick", "room.table.unknown")
+	require.NoError(t, err)
+	assert.Len(t, s.Routes, 1)
+	assert.Equal(t, "proto.mcbeam.KickAnswer", s.Routes["room.table.kick"].Response)
+	assert.NotEmpty(t, s.Version)
+}
+
+func TestMerge(t *testing.T) {
+	Register("room.table.kick", reflect.TypeOf(&proto_mcbeam.KickMsg{}), reflect.TypeOf(&proto_mcbeam.KickAnswer{}))
+	Register("chat.channel.send", reflect.TypeOf(&proto_mcbeam.PushMsg{}), nil)
+	room, err := Of("room.table.kick")
+	require.NoError(t, err)
+	chat, err := Of("chat.channel.send")
+	require.NoError(t, err)
+
+	s, err := Merge(room, chat)
+	require.NoError(t, err)
+	assert.Len(t, s.Routes, 2)
+	assert.Equal(t, "proto.mcbeam.PushMsg", s.Routes["chat.channel.send"].Request)
+
+	// both servers declare their messages in the same file, it is sent once
+	set := &descriptorpb.FileDescriptorSet{}
+	require.NoError(t, protov2.Unmarshal(s.Descriptors, set))
+	names := map[string]int{}
+	for _, fd := range set.File {
+		names[fd.GetName()]++
+	}
+	for name, n := range names {
+		assert.Equal(t, 1, n, name)
+	}
+
+	// the order of the servers does not change the version
+	again, err := Merge(chat, room)
+	require.NoError(t, err)
+	assert.Equal(t, s.Version, again.Version)
+	assert.NotEqual(t, room.Version, s.Version)
+}
@@ -0,0 +1,258 @@
+package session
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"reflect"
+	"sync"
+)
+
+// DataCodec encodes the session data sent between frontend and backend
+// servers, all the servers of a cluster must use the same codec
+type DataCodec interface {
+	Encode(data map[string]interface{}) ([]byte, error)
+	Decode(b []byte) (map[string]interface{}, error)
+}
+
+var dataCodec DataCodec = NewTypedDataCodec()
+
+// SetDataCodec sets the codec of the session data, it must be called
+// before any session is created
+func SetDataCodec(c DataCodec) {
+	dataCodec = c
+}
+
+// JSONDataCodec encodes the session data as plain json, numbers are
+// decoded as float64
+type JSONDataCodec struct{}
+
+// Encode encodes the session data
+func (JSONDataCodec) Encode(data map[string]interface{}) ([]byte, error) {
+	return json.Marshal(data)
+}
+
+// Decode decodes the session data
+func (JSONDataCodec) Decode(b []byte) (map[string]interface{}, error) {
+	var data map[string]interface{}
+	err := json.Unmarshal(b, &data)
+	return data, err
+}
+
+// typedValue is a session value along with the name of its go type
+type typedValue struct {
+	Type  string          `json:"t"`
+	Value json.RawMessage `json:"v,omitempty"`
+}
+
+// TypedDataCodec encodes the session data as json keeping the go type of
+// each value, so an int set on a frontend is an int on the backends.
+// Values of types not registered with RegisterDataType are decoded the
+// way encoding/json does, with whole numbers as int64.
+type TypedDataCodec struct {
+	mutex sync.RWMutex
+	types map[string]reflect.Type
+	names map[reflect.Type]string
+}
+
+// NewTypedDataCodec returns a typed codec knowing the go basic types
+func NewTypedDataCodec() *TypedDataCodec {
+	c := &TypedDataCodec{
+		types: make(map[string]reflect.Type),
+		names: make(map[reflect.Type]string),
+	}
+	for _, v := range []interface{}{
+		int(0), int8(0), int16(0), int32(0), int64(0),
+		uint(0), uint8(0), uint16(0), uint32(0), uint64(0),
+		float32(0), float64(0), "", false, []byte(nil),
+	} {
+		c.RegisterDataType(v)
+	}
+	return c
+}
+
+// RegisterDataType makes the values of the type of v decode to that type,
+// structs stored in sessions must be registered on every server. Types
+// are named by their full package path, so servers built from different
+// module layouts should use RegisterDataTypeName instead
+func (c *TypedDataCodec) RegisterDataType(v interface{}) {
+	t := reflect.TypeOf(v)
+	c.RegisterDataTypeName(typeName(t), v)
+}
+
+// RegisterDataTypeName makes the values of the type of v travel under
+// name, the same name must be registered for the type on every server
+func (c *TypedDataCodec) RegisterDataTypeName(name string, v interface{}) {
+	t := reflect.TypeOf(v)
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.types[name] = t
+	c.names[t] = name
+}
+
+// name returns the name the values of t are encoded with
+func (c *TypedDataCodec) name(t reflect.Type) string {
+	c.mutex.RLock()
+	name, ok := c.names[t]
+	c.mutex.RUnlock()
+	if ok {
+		return name
+	}
+	return typeName(t)
+}
+
+// typeName names t by its package path rather than by the package name
+// reflect.Type.String uses, so same named types of two packages do not
+// collide
+func typeName(t reflect.Type) string {
+	if t.Kind() == reflect.Ptr {
+		return "*" + typeName(t.Elem())
+	}
+	if t.Name() == "" || t.PkgPath() == "" {
+		return t.String()
+	}
+	return t.PkgPath() + "." + t.Name()
+}
+
+// RegisterDataType registers the type of v on the codec in use, if it is
+// a TypedDataCodec
+func RegisterDataType(v interface{}) {
+	if c, ok := dataCodec.(*TypedDataCodec); ok {
+		c.RegisterDataType(v)
+	}
+}
+
+// RegisterDataTypeName registers the type of v under name on the codec in
+// use, if it is a TypedDataCodec
+func RegisterDataTypeName(name string, v interface{}) {
+	if c, ok := dataCodec.(*TypedDataCodec); ok {
+		c.RegisterDataTypeName(name, v)
+	}
+}
+
+// Encode encodes the session data
+func (c *TypedDataCodec) Encode(data map[string]interface{}) ([]byte, error) {
+	values := make(map[string]typedValue, len(data))
+	for k, v := range data {
+		if v == nil {
+			values[k] = typedValue{}
+			continue
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		values[k] = typedValue{Type: c.name(reflect.TypeOf(v)), Value: b}
+	}
+	return json.Marshal(values)
+}
+
+// Decode decodes the session data
+func (c *TypedDataCodec) Decode(b []byte) (map[string]interface{}, error) {
+	var values map[string]typedValue
+	if err := json.Unmarshal(b, &values); err != nil {
+		return nil, err
+	}
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	data := make(map[string]interface{}, len(values))
+	for k, tv := range values {
+		if tv.Type == "" {
+			data[k] = nil
+			continue
+		}
+		if t, ok := c.types[tv.Type]; ok {
+			v := reflect.New(t)
+			if err := json.Unmarshal(tv.Value, v.Interface()); err != nil {
+				return nil, err
+			}
+			data[k] = v.Elem().Interface()
+			continue
+		}
+		var v interface{}
+		dec := json.NewDecoder(bytes.NewReader(tv.Value))
+		dec.UseNumber()
+		if err := dec.Decode(&v); err != nil {
+			return nil, err
+		}
+		data[k] = normalizeNumbers(v)
+	}
+	return data, nil
+}
+
+// normalizeNumbers replaces the json numbers in v by int64, or float64
+// when they are not whole
+func normalizeNumbers(v interface{}) interface{} {
+	switch value := v.(type) {
+	case json.Number:
+		if i, err := value.Int64(); err == nil {
+			return i
+		}
+		f, _ := value.Float64()
+		return f
+	case map[string]interface{}:
+		for k, e := range value {
+			value[k] = normalizeNumbers(e)
+		}
+	case []interface{}:
+		for i, e := range value {
+			value[i] = normalizeNumbers(e)
+		}
+	}
+	return v
+}
+
+// toInt64 converts any go number to int64
+func toInt64(v interface{}) int64 {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return int64(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return int64(rv.Float())
+	}
+	if n, ok := v.(json.Number); ok {
+		if i, err := n.Int64(); err == nil {
+			return i
+		}
+		f, _ := n.Float64()
+		return int64(f)
+	}
+	return 0
+}
+
+// toUint64 converts any go number to uint64, negative numbers are 0
+func toUint64(v interface{}) uint64 {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return rv.Uint()
+	case reflect.Float32, reflect.Float64:
+		if f := rv.Float(); f > 0 && f < math.MaxUint64 {
+			return uint64(f)
+		}
+		return 0
+	}
+	if i := toInt64(v); i > 0 {
+		return uint64(i)
+	}
+	return 0
+}
+
+// toFloat64 converts any go number to float64
+func toFloat64(v interface{}) float64 {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return rv.Float()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(rv.Uint())
+	}
+	if n, ok := v.(json.Number); ok {
+		f, _ := n.Float64()
+		return f
+	}
+	return float64(toInt64(v))
+}
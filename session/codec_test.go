@@ -0,0 +1,108 @@
+package session
+
+import (
+	htemplate "html/template"
+	"reflect"
+	"testing"
+	ttemplate "text/template"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type profile struct {
+	Name  string
+	Level int
+}
+
+func TestTypedDataCodecKeepsTypes(t *testing.T) {
+	c := NewTypedDataCodec()
+	c.RegisterDataType(profile{})
+	data := map[string]interface{}{
+		"int":     42,
+		"int64":   int64(1) << 40,
+		"uint32":  uint32(7),
+		"float32": float32(1.5),
+		"string":  "hello",
+		"bool":    true,
+		"bytes":   []byte{1, 2, 3},
+		"nil":     nil,
+		"profile": profile{Name: "wolf", Level: 3},
+		"list":    []interface{}{1, 2.5, "a"},
+	}
+	b, err := c.Encode(data)
+	assert.NoError(t, err)
+	decoded, err := c.Decode(b)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 42, decoded["int"])
+	assert.Equal(t, int64(1)<<40, decoded["int64"])
+	assert.Equal(t, uint32(7), decoded["uint32"])
+	assert.Equal(t, float32(1.5), decoded["float32"])
+	assert.Equal(t, "hello", decoded["string"])
+	assert.Equal(t, true, decoded["bool"])
+	assert.Equal(t, []byte{1, 2, 3}, decoded["bytes"])
+	assert.Contains(t, decoded, "nil")
+	assert.Nil(t, decoded["nil"])
+	assert.Equal(t, profile{Name: "wolf", Level: 3}, decoded["profile"])
+	assert.Equal(t, []interface{}{int64(1), 2.5, "a"}, decoded["list"])
+}
+
+func TestTypedDataCodecUnregisteredStruct(t *testing.T) {
+	c := NewTypedDataCodec()
+	b, err := c.Encode(map[string]interface{}{"profile": profile{Name: "wolf", Level: 3}})
+	assert.NoError(t, err)
+	decoded, err := c.Decode(b)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"Name": "wolf", "Level": int64(3)}, decoded["profile"])
+}
+
+func TestTypeNames(t *testing.T) {
+	tables := []struct {
+		v    interface{}
+		name string
+	}{
+		{0, "int"},
+		{[]byte(nil), "[]uint8"},
+		{profile{}, "github.com/wolfplus2048/mcbeam-plus/session.profile"},
+		{&profile{}, "*github.com/wolfplus2048/mcbeam-plus/session.profile"},
+		{ttemplate.Template{}, "text/template.Template"},
+		{htemplate.Template{}, "html/template.Template"},
+	}
+	for _, table := range tables {
+		assert.Equal(t, table.name, typeName(reflect.TypeOf(table.v)))
+	}
+}
+
+func TestTypedDataCodecTypeName(t *testing.T) {
+	c := NewTypedDataCodec()
+	c.RegisterDataTypeName("game.Profile", profile{})
+	b, err := c.Encode(map[string]interface{}{"profile": profile{Name: "wolf", Level: 3}})
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), `"t":"game.Profile"`)
+
+	decoded, err := c.Decode(b)
+	assert.NoError(t, err)
+	assert.Equal(t, profile{Name: "wolf", Level: 3}, decoded["profile"])
+}
+
+func TestNumericConversions(t *testing.T) {
+	tables := []struct {
+		in interface{}
+		i  int64
+		u  uint64
+		f  float64
+	}{
+		{int(3), 3, 3, 3},
+		{int8(-2), -2, 0, -2},
+		{uint16(9), 9, 9, 9},
+		{float64(2.75), 2, 2, 2.75},
+		{float32(-1), -1, 0, -1},
+		{"3", 0, 0, 0},
+		{nil, 0, 0, 0},
+	}
+	for _, table := range tables {
+		assert.Equal(t, table.i, toInt64(table.in))
+		assert.Equal(t, table.u, toUint64(table.in))
+		assert.Equal(t, table.f, toFloat64(table.in))
+	}
+}
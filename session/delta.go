@@ -0,0 +1,127 @@
+package session
+
+import (
+	"github.com/wolfplus2048/mcbeam-plus/constants"
+	"github.com/wolfplus2048/mcbeam-plus/protos"
+)
+
+// Version returns the version of the session data, frontends bump it on
+// each change and backends get it along with the data
+func (s *Session) Version() uint64 {
+	s.RLock()
+	defer s.RUnlock()
+	return s.version
+}
+
+// SetVersion sets the version the session data was read at
+func (s *Session) SetVersion(version uint64) {
+	s.Lock()
+	defer s.Unlock()
+	s.version = version
+}
+
+// changed records the keys as changed, s must be locked
+func (s *Session) changed(keys ...string) {
+	if s.IsFrontend {
+		s.version++
+		for _, k := range keys {
+			s.keyVersions[k] = s.version
+		}
+		return
+	}
+	for _, k := range keys {
+		s.dirty[k] = struct{}{}
+	}
+}
+
+// takeDelta returns the changes to push to the frontend and the keys they
+// are for, it returns a nil delta when nothing changed
+func (s *Session) takeDelta() (*proto_mcbeam.SessionDelta, []string, error) {
+	s.Lock()
+	defer s.Unlock()
+	if len(s.dirty) == 0 {
+		return nil, nil, nil
+	}
+	set := make(map[string]interface{})
+	keys := make([]string, 0, len(s.dirty))
+	var removed []string
+	for k := range s.dirty {
+		keys = append(keys, k)
+		if v, ok := s.data[k]; ok {
+			set[k] = v
+		} else {
+			removed = append(removed, k)
+		}
+	}
+	data, err := dataCodec.Encode(set)
+	if err != nil {
+		return nil, nil, err
+	}
+	s.dirty = make(map[string]struct{})
+	return &proto_mcbeam.SessionDelta{
+		Id:      s.frontendSessionID,
+		Uid:     s.UID(),
+		Version: s.version,
+		Data:    data,
+		Removed: removed,
+	}, keys, nil
+}
+
+// ApplyDelta applies the changes a backend made to the session data read
+// at version base and returns the new version. When the data changed since
+// base the delta is rejected with ErrStaleSessionData, unless merge is set
+// and none of its keys changed.
+func (s *Session) ApplyDelta(base uint64, data []byte, removed []string, merge bool) (uint64, error) {
+	set := map[string]interface{}{}
+	if len(data) > 0 {
+		var err error
+		if set, err = dataCodec.Decode(data); err != nil {
+			return 0, err
+		}
+	}
+	version, err := s.applyDelta(base, set, removed, merge)
+	if err != nil {
+		return 0, err
+	}
+	s.dataChanged()
+	return version, nil
+}
+
+func (s *Session) applyDelta(base uint64, set map[string]interface{}, removed []string, merge bool) (uint64, error) {
+	keys := make([]string, 0, len(set)+len(removed))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	keys = append(keys, removed...)
+
+	s.Lock()
+	defer s.Unlock()
+	if base != s.version {
+		if !merge {
+			return 0, constants.ErrStaleSessionData
+		}
+		for _, k := range keys {
+			if s.keyVersions[k] > base {
+				return 0, constants.ErrStaleSessionData
+			}
+		}
+	}
+	for k, v := range set {
+		s.data[k] = v
+	}
+	for _, k := range removed {
+		delete(s.data, k)
+	}
+	s.changed(keys...)
+	if err := s.updateEncodedData(); err != nil {
+		return 0, err
+	}
+	return s.version, nil
+}
+
+// Snapshot returns the encoded session data along with its version
+func (s *Session) Snapshot() ([]byte, uint64) {
+	s.RLock()
+	defer s.RUnlock()
+	return s.encodedData, s.version
+}
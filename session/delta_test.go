@@ -0,0 +1,70 @@
+package session
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wolfplus2048/mcbeam-plus/constants"
+)
+
+func TestPushedDelta(t *testing.T) {
+	backend := New(nil, false)
+	assert.NoError(t, backend.SetDataEncoded(mustEncode(t, map[string]interface{}{"a": 1, "b": 2})))
+	backend.SetVersion(3)
+
+	delta, keys, err := backend.takeDelta()
+	assert.NoError(t, err)
+	assert.Nil(t, delta)
+	assert.Empty(t, keys)
+
+	assert.NoError(t, backend.Set("a", 10))
+	assert.NoError(t, backend.Remove("b"))
+	delta, keys, err = backend.takeDelta()
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"a", "b"}, keys)
+	assert.Equal(t, uint64(3), delta.GetVersion())
+	assert.Equal(t, []string{"b"}, delta.GetRemoved())
+	set, err := dataCodec.Decode(delta.GetData())
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"a": 10}, set)
+	assert.Empty(t, backend.dirty)
+}
+
+func TestApplyDelta(t *testing.T) {
+	tables := []struct {
+		name  string
+		base  uint64
+		key   string
+		merge bool
+		err   error
+	}{
+		{"current version", 2, "a", false, nil},
+		{"stale version", 1, "a", false, constants.ErrStaleSessionData},
+		{"stale version merged", 1, "a", true, nil},
+		{"stale key", 1, "b", true, constants.ErrStaleSessionData},
+	}
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			frontend := New(nil, true)
+			defer sessionsByID.Delete(frontend.ID())
+			assert.NoError(t, frontend.Set("a", 1))
+			assert.NoError(t, frontend.Set("b", 2))
+
+			data := mustEncode(t, map[string]interface{}{table.key: 3})
+			version, err := frontend.ApplyDelta(table.base, data, nil, table.merge)
+			assert.Equal(t, table.err, err)
+			if table.err != nil {
+				assert.Equal(t, uint64(2), frontend.Version())
+				return
+			}
+			assert.Equal(t, uint64(3), version)
+			assert.Equal(t, 3, frontend.Int(table.key))
+		})
+	}
+}
+
+func mustEncode(t *testing.T, data map[string]interface{}) []byte {
+	b, err := dataCodec.Encode(data)
+	assert.NoError(t, err)
+	return b
+}
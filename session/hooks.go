@@ -0,0 +1,271 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/micro/go-micro/v2/logger"
+	"github.com/wolfplus2048/mcbeam-plus/protos"
+)
+
+// DefaultHookTimeout is how long a hook can run before the next one is
+// started, the late hook keeps running in the background
+const DefaultHookTimeout = 5 * time.Second
+
+var (
+	bindHooks        = newHookRegistry("bind")
+	afterBindHooks   = newHookRegistry("after_bind")
+	closeHooks       = newHookRegistry("close")
+	remoteCloseHooks = newHookRegistry("remote_close")
+	dataChangeHooks  = newHookRegistry("data_change")
+
+	// SessionCloseCallbacks contains global session close callbacks, they
+	// run after the OnSessionClose hooks.
+	//
+	// Deprecated: use OnSessionClose, whose handle removes the callback.
+	SessionCloseCallbacks = make([]func(s *Session), 0)
+)
+
+// HookOption configures a session hook
+type HookOption func(*hookEntry)
+
+// HookPriority sets the priority of the hook, hooks with higher priorities
+// run first and hooks with the same priority in registration order
+func HookPriority(p int) HookOption {
+	return func(e *hookEntry) {
+		e.priority = p
+	}
+}
+
+// HookTimeout sets how long the hook can run, zero waits for it forever.
+// Bind hooks get it as the deadline of their context.
+func HookTimeout(d time.Duration) HookOption {
+	return func(e *hookEntry) {
+		e.timeout = d
+	}
+}
+
+// HookHandle unregisters the hook it was returned for
+type HookHandle struct {
+	registry *hookRegistry
+	id       uint64
+}
+
+// Remove unregisters the hook, it can be called more than once
+func (h *HookHandle) Remove() {
+	h.registry.remove(h.id)
+}
+
+type hookEntry struct {
+	id       uint64
+	priority int
+	timeout  time.Duration
+	fn       interface{}
+}
+
+type hookRegistry struct {
+	name    string
+	mutex   sync.RWMutex
+	seq     uint64
+	entries []*hookEntry
+}
+
+func newHookRegistry(name string) *hookRegistry {
+	return &hookRegistry{name: name}
+}
+
+func (r *hookRegistry) add(fn interface{}, opts []HookOption) *HookHandle {
+	e := &hookEntry{fn: fn, timeout: DefaultHookTimeout}
+	for _, o := range opts {
+		o(e)
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.seq++
+	e.id = r.seq
+	i := sort.Search(len(r.entries), func(i int) bool {
+		return r.entries[i].priority < e.priority
+	})
+	entries := make([]*hookEntry, 0, len(r.entries)+1)
+	entries = append(entries, r.entries[:i]...)
+	entries = append(entries, e)
+	r.entries = append(entries, r.entries[i:]...)
+	return &HookHandle{registry: r, id: e.id}
+}
+
+func (r *hookRegistry) remove(id uint64) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for i, e := range r.entries {
+		if e.id == id {
+			entries := make([]*hookEntry, 0, len(r.entries)-1)
+			entries = append(entries, r.entries[:i]...)
+			r.entries = append(entries, r.entries[i+1:]...)
+			return
+		}
+	}
+}
+
+// list returns the hooks in running order, entries are never modified in
+// place so the slice can be iterated unlocked
+func (r *hookRegistry) list() []*hookEntry {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.entries
+}
+
+// runBind runs the bind hooks until one of them fails
+func (r *hookRegistry) runBind(ctx context.Context, s *Session) error {
+	for _, e := range r.list() {
+		if err := r.callBind(ctx, e, s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *hookRegistry) callBind(ctx context.Context, e *hookEntry, s *Session) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			r.failed("panic", rec)
+			err = fmt.Errorf("session %s hook panicked: %v", r.name, rec)
+		}
+	}()
+	if e.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.timeout)
+		defer cancel()
+	}
+	err = e.fn.(func(context.Context, *Session) error)(ctx, s)
+	if err != nil {
+		r.failed("error", err)
+	}
+	return err
+}
+
+// run calls each hook through call, a panicking or late hook does not stop
+// the ones after it
+func (r *hookRegistry) run(call func(fn interface{})) {
+	for _, e := range r.list() {
+		fn := e.fn
+		r.call(e.timeout, func() { call(fn) })
+	}
+}
+
+func (r *hookRegistry) call(timeout time.Duration, fn func()) {
+	if timeout <= 0 {
+		r.safeCall(fn)
+		return
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		r.safeCall(fn)
+	}()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+	case <-timer.C:
+		r.failed("timeout", timeout)
+	}
+}
+
+func (r *hookRegistry) safeCall(fn func()) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			r.failed("panic", rec)
+		}
+	}()
+	fn()
+}
+
+func (r *hookRegistry) failed(reason string, detail interface{}) {
+	hookFailures.WithLabelValues(r.name, reason).Inc()
+	logger.Errorf("Session %s hook failed, %s: %v", r.name, reason, detail)
+}
+
+// OnSessionBind adds a method to be called when a session is bound, the
+// bind fails when it returns an error
+func OnSessionBind(f func(ctx context.Context, s *Session) error, opts ...HookOption) *HookHandle {
+	return bindHooks.add(f, opts)
+}
+
+// OnAfterSessionBind adds a method to be called when session is bound and after all sessionBind callbacks
+func OnAfterSessionBind(f func(ctx context.Context, s *Session) error, opts ...HookOption) *HookHandle {
+	return afterBindHooks.add(f, opts)
+}
+
+// OnSessionClose adds a method that will be called when every session closes
+func OnSessionClose(f func(s *Session), opts ...HookOption) *HookHandle {
+	return closeHooks.add(f, opts)
+}
+
+// OnRemoteSessionClose adds a method that will be called when a bound
+// session closes on any frontend, it is meant for backend servers
+func OnRemoteSessionClose(f func(uid string), opts ...HookOption) *HookHandle {
+	return remoteCloseHooks.add(f, opts)
+}
+
+// OnRemoteSessionCloseWithID is OnRemoteSessionClose along with the
+// frontend id and the session id, they tell the close of an old session
+// of the uid from the close of the one it is bound to now
+func OnRemoteSessionCloseWithID(f func(uid, frontendID string, id int64), opts ...HookOption) *HookHandle {
+	return remoteCloseHooks.add(f, opts)
+}
+
+// OnSessionDataChange adds a method that will be called after the data of
+// any frontend session changed
+func OnSessionDataChange(f func(s *Session), opts ...HookOption) *HookHandle {
+	return dataChangeHooks.add(f, opts)
+}
+
+// HandleRemoteSessionClose calls the OnRemoteSessionClose callbacks, it
+// subscribes to the SessionClose events frontends publish
+func HandleRemoteSessionClose(ctx context.Context, msg *proto_mcbeam.SessionClose) error {
+	remoteCloseHooks.run(func(fn interface{}) {
+		switch f := fn.(type) {
+		case func(string):
+			f(msg.GetUid())
+		case func(string, string, int64):
+			f(msg.GetUid(), msg.GetFrontendID(), msg.GetId())
+		}
+	})
+	return nil
+}
+
+func (s *Session) dataChanged() {
+	if !s.IsFrontend {
+		return
+	}
+	dataChangeHooks.run(func(fn interface{}) {
+		fn.(func(*Session))(s)
+	})
+}
+
+// RunCloseCallbacks runs the OnClose callbacks of the frontend session and
+// the OnSessionClose ones, only the first call runs them. The network
+// entity calls it once its connection is closed.
+func (s *Session) RunCloseCallbacks() {
+	if !s.IsFrontend {
+		return
+	}
+	s.closeOnce.Do(func() {
+		s.RLock()
+		callbacks := s.OnCloseCallbacks
+		s.RUnlock()
+		for _, fn := range callbacks {
+			closeHooks.call(DefaultHookTimeout, fn)
+		}
+		closeHooks.run(func(fn interface{}) {
+			fn.(func(*Session))(s)
+		})
+		for _, fn := range SessionCloseCallbacks {
+			fn := fn
+			closeHooks.call(DefaultHookTimeout, func() { fn(s) })
+		}
+	})
+}
@@ -0,0 +1,124 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wolfplus2048/mcbeam-plus/protos"
+)
+
+func TestHookPriorityAndRemoval(t *testing.T) {
+	r := newHookRegistry("test")
+	var order []string
+	hook := func(name string) func(context.Context, *Session) error {
+		return func(context.Context, *Session) error {
+			order = append(order, name)
+			return nil
+		}
+	}
+	r.add(hook("low"), []HookOption{HookPriority(-1)})
+	r.add(hook("default"), nil)
+	h := r.add(hook("removed"), nil)
+	r.add(hook("high"), []HookOption{HookPriority(10)})
+	r.add(hook("default2"), nil)
+
+	h.Remove()
+	h.Remove()
+	assert.NoError(t, r.runBind(context.Background(), nil))
+	assert.Equal(t, []string{"high", "default", "default2", "low"}, order)
+}
+
+func TestBindHookFailures(t *testing.T) {
+	r := newHookRegistry("test")
+	errHook := errors.New("hook error")
+	called := false
+	r.add(func(context.Context, *Session) error { return errHook }, nil)
+	r.add(func(context.Context, *Session) error {
+		called = true
+		return nil
+	}, nil)
+	assert.Equal(t, errHook, r.runBind(context.Background(), nil))
+	assert.False(t, called)
+
+	r = newHookRegistry("test")
+	r.add(func(context.Context, *Session) error { panic("boom") }, nil)
+	assert.Error(t, r.runBind(context.Background(), nil))
+}
+
+func TestBindHookDeadline(t *testing.T) {
+	r := newHookRegistry("test")
+	r.add(func(ctx context.Context, _ *Session) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}, []HookOption{HookTimeout(10 * time.Millisecond)})
+	assert.Equal(t, context.DeadlineExceeded, r.runBind(context.Background(), nil))
+}
+
+func TestHooksSurvivePanicsAndTimeouts(t *testing.T) {
+	r := newHookRegistry("test")
+	release := make(chan struct{})
+	defer close(release)
+	var called []string
+	r.add(func() { panic("boom") }, nil)
+	r.add(func() { <-release }, []HookOption{HookTimeout(10 * time.Millisecond)})
+	r.add(func() { called = append(called, "last") }, nil)
+
+	r.run(func(fn interface{}) { fn.(func())() })
+	assert.Equal(t, []string{"last"}, called)
+}
+
+func TestRunCloseCallbacksOnce(t *testing.T) {
+	s := New(nil, true)
+	defer sessionsByID.Delete(s.ID())
+	calls := 0
+	assert.NoError(t, s.OnClose(func() { calls++ }))
+	closed := 0
+	h := OnSessionClose(func(c *Session) {
+		if c == s {
+			closed++
+		}
+	})
+	defer h.Remove()
+
+	legacy := 0
+	SessionCloseCallbacks = append(SessionCloseCallbacks, func(c *Session) {
+		if c == s {
+			legacy++
+		}
+	})
+	defer func() { SessionCloseCallbacks = SessionCloseCallbacks[:0] }()
+
+	s.RunCloseCallbacks()
+	s.RunCloseCallbacks()
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, 1, closed)
+	assert.Equal(t, 1, legacy)
+}
+
+func TestHandleRemoteSessionClose(t *testing.T) {
+	type closed struct {
+		uid, frontendID string
+		id              int64
+	}
+	var got []closed
+	var uids []string
+	h := OnRemoteSessionCloseWithID(func(uid, frontendID string, id int64) {
+		got = append(got, closed{uid, frontendID, id})
+	})
+	hu := OnRemoteSessionClose(func(uid string) {
+		uids = append(uids, uid)
+	})
+	defer hu.Remove()
+	msg := &proto_mcbeam.SessionClose{Uid: "u1", FrontendID: "gate-1", Id: 7}
+	assert.NoError(t, HandleRemoteSessionClose(context.Background(), msg))
+	assert.Equal(t, []closed{{"u1", "gate-1", 7}}, got)
+	assert.Equal(t, []string{"u1"}, uids)
+
+	h.Remove()
+	assert.NoError(t, HandleRemoteSessionClose(context.Background(), msg))
+	assert.Len(t, got, 1)
+	assert.Equal(t, []string{"u1", "u1"}, uids)
+}
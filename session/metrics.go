@@ -0,0 +1,16 @@
+package session
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var hookFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "mcbeam",
+	Subsystem: "session",
+	Name:      "hook_failures_total",
+	Help:      "the number of session hooks that returned an error, panicked or timed out",
+}, []string{"hook", "reason"})
+
+func init() {
+	prometheus.MustRegister(hookFailures)
+}
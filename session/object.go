@@ -0,0 +1,79 @@
+package session
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/wolfplus2048/mcbeam-plus/constants"
+	"github.com/wolfplus2048/mcbeam-plus/serialize"
+	"github.com/wolfplus2048/mcbeam-plus/serialize/json"
+)
+
+var (
+	objectSerializer serialize.Serializer = json.NewSerializer()
+	objectTypesMutex sync.RWMutex
+	objectTypes      = make(map[string]reflect.Type)
+)
+
+// SetObjectSerializer sets the serializer SetObject and GetObject go
+// through, json by default
+func SetObjectSerializer(s serialize.Serializer) {
+	objectSerializer = s
+}
+
+// RegisterObjectType makes SetObject reject the values of the key that are
+// not of the type of v, v can be either a value or a pointer to it
+func RegisterObjectType(key string, v interface{}) {
+	objectTypesMutex.Lock()
+	defer objectTypesMutex.Unlock()
+	objectTypes[key] = indirectType(reflect.TypeOf(v))
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+func checkObjectType(key string, v interface{}) error {
+	objectTypesMutex.RLock()
+	defer objectTypesMutex.RUnlock()
+	t, ok := objectTypes[key]
+	if ok && indirectType(reflect.TypeOf(v)) != t {
+		return constants.ErrWrongObjectType
+	}
+	return nil
+}
+
+// SetObject serializes v and associates it with the key in session
+// storage, it fails when a different type is registered for the key
+func (s *Session) SetObject(key string, v interface{}) error {
+	if err := checkObjectType(key, v); err != nil {
+		return err
+	}
+	b, err := objectSerializer.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return s.Set(key, b)
+}
+
+// GetObject unserializes the value associated with the key into the value
+// pointed to by v, it fails with ErrObjectNotFound when the key is unset
+func (s *Session) GetObject(key string, v interface{}) error {
+	if err := checkObjectType(key, v); err != nil {
+		return err
+	}
+	s.RLock()
+	value, ok := s.data[key]
+	s.RUnlock()
+	if !ok {
+		return constants.ErrObjectNotFound
+	}
+	b, ok := value.([]byte)
+	if !ok {
+		return constants.ErrWrongObjectType
+	}
+	return objectSerializer.Unmarshal(b, v)
+}
@@ -0,0 +1,58 @@
+package session
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wolfplus2048/mcbeam-plus/constants"
+)
+
+type player struct {
+	Name  string
+	Coins int64
+}
+
+func TestSessionObject(t *testing.T) {
+	s := New(nil, false)
+	in := player{Name: "wolf", Coins: 100}
+	assert.NoError(t, s.SetObject("player", &in))
+
+	var out player
+	assert.NoError(t, s.GetObject("player", &out))
+	assert.Equal(t, in, out)
+
+	assert.Equal(t, constants.ErrObjectNotFound, s.GetObject("missing", &out))
+	assert.NoError(t, s.Set("name", "wolf"))
+	assert.Equal(t, constants.ErrWrongObjectType, s.GetObject("name", &out))
+}
+
+func TestSessionObjectSurvivesEncoding(t *testing.T) {
+	s := New(nil, false)
+	assert.NoError(t, s.SetObject("player", player{Name: "wolf", Coins: 100}))
+	other := New(nil, false)
+	assert.NoError(t, other.SetDataEncoded(s.GetDataEncoded()))
+
+	var out player
+	assert.NoError(t, other.GetObject("player", &out))
+	assert.Equal(t, player{Name: "wolf", Coins: 100}, out)
+}
+
+func TestRegisteredObjectType(t *testing.T) {
+	RegisterObjectType("registered", player{})
+	defer func() {
+		objectTypesMutex.Lock()
+		delete(objectTypes, "registered")
+		objectTypesMutex.Unlock()
+	}()
+
+	s := New(nil, false)
+	assert.NoError(t, s.SetObject("registered", player{Name: "wolf"}))
+	assert.NoError(t, s.SetObject("registered", &player{Name: "wolf"}))
+	assert.Equal(t, constants.ErrWrongObjectType, s.SetObject("registered", "wolf"))
+
+	var name string
+	assert.Equal(t, constants.ErrWrongObjectType, s.GetObject("registered", &name))
+	var out player
+	assert.NoError(t, s.GetObject("registered", &out))
+	assert.Equal(t, "wolf", out.Name)
+}
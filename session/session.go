@@ -22,13 +22,10 @@ package session
 
 import (
 	"context"
-	"encoding/json"
 	"github.com/micro/go-micro/v2/logger"
 	"github.com/wolfplus2048/mcbeam-plus/constants"
 	"github.com/wolfplus2048/mcbeam-plus/protos"
 	"net"
-	"reflect"
-	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -47,23 +44,40 @@ type NetworkEntity interface {
 }
 
 var (
-	sessionBindCallbacks = make([]func(ctx context.Context, s *Session) error, 0)
-	afterBindCallbacks   = make([]func(ctx context.Context, s *Session) error, 0)
-	// SessionCloseCallbacks contains global session close callbacks
-	SessionCloseCallbacks = make([]func(s *Session), 0)
-	sessionsByUID         sync.Map
-	sessionsByID          sync.Map
-	sessionIDSvc          = newSessionIDService()
+	sessionsByUID sync.Map
+	sessionsByID  sync.Map
+	sessionIDSvc  = newSessionIDService()
 	// SessionCount keeps the current number of sessions
 	SessionCount int64
 )
 
+// Reasons a frontend session can be closed for, see Session.CloseReason
+const (
+	CloseReasonDisconnect       = "disconnect"
+	CloseReasonHeartbeatTimeout = "heartbeat timeout"
+	CloseReasonKick             = "kick"
+	CloseReasonShutdown         = "shutdown"
+	CloseReasonBufferExceed     = "send buffer exceed"
+	CloseReasonRateLimit        = "rate limit exceeded"
+	CloseReasonLoginElsewhere   = "logged in elsewhere"
+)
+
+// Transports a client can be connected with, see Session.Transport
+const (
+	TransportTCP = "tcp"
+	TransportWS  = "ws"
+)
+
 // HandshakeClientData represents information about the client sent on the handshake.
 type HandshakeClientData struct {
 	Platform    string `json:"platform"`
 	LibVersion  string `json:"libVersion"`
 	BuildNumber string `json:"clientBuildNumber"`
 	Version     string `json:"clientVersion"`
+	// ProtoVersion is the version of the protobuf schema cached by the client
+	ProtoVersion string `json:"protoVersion,omitempty"`
+	// ResumeToken is sent by reconnecting clients to get their session back
+	ResumeToken string `json:"resumeToken,omitempty"`
 }
 
 // HandshakeData represents information about the handshake sent by the client.
@@ -81,8 +95,12 @@ type HandshakeData struct {
 type Session struct {
 	sync.RWMutex                             // protect data
 	id                int64                  // session global unique id
-	uid               string                 // binding user id
+	uid               atomic.Value           // binding user id, read by other goroutines than Bind
 	lastTime          int64                  // last heartbeat time
+	createdAt         int64                  // creation time
+	closeReason       atomic.Value           // why the session was closed
+	closeOnce         sync.Once              // runs the close callbacks once
+	countOnce         sync.Once              // decrements SessionCount once
 	entity            NetworkEntity          // low-level network entity
 	data              map[string]interface{} // session data store
 	handshakeData     *HandshakeData         // handshake data received by the client
@@ -92,6 +110,9 @@ type Session struct {
 	frontendID        string                 // the id of the frontend that owns the session
 	frontendSessionID int64                  // the id of the session on the frontend tcp
 	Subscriptions     []*nats.Subscription   // subscription created on bind when using nats rpc tcp
+	version           uint64                 // version of the data, bumped by each change on the frontend
+	keyVersions       map[string]uint64      // version each key last changed at, on the frontend
+	dirty             map[string]struct{}    // keys changed since the last push to the frontend, on backends
 }
 
 type sessionIDService struct {
@@ -118,15 +139,18 @@ func New(entity NetworkEntity, frontend bool, UID ...string) *Session {
 		data:             make(map[string]interface{}),
 		handshakeData:    nil,
 		lastTime:         time.Now().Unix(),
+		createdAt:        time.Now().Unix(),
 		OnCloseCallbacks: []func(){},
 		IsFrontend:       frontend,
+		keyVersions:      make(map[string]uint64),
+		dirty:            make(map[string]struct{}),
 	}
 	if frontend {
 		sessionsByID.Store(s.id, s)
 		atomic.AddInt64(&SessionCount, 1)
 	}
 	if len(UID) > 0 {
-		s.uid = UID[0]
+		s.uid.Store(UID[0])
 	}
 	return s
 }
@@ -149,43 +173,11 @@ func GetSessionByID(id int64) *Session {
 	return nil
 }
 
-// OnSessionBind adds a method to be called when a session is bound
-// same function cannot be added twice!
-func OnSessionBind(f func(ctx context.Context, s *Session) error) {
-	// Prevents the same function to be added twice in onSessionBind
-	sf1 := reflect.ValueOf(f)
-	for _, fun := range sessionBindCallbacks {
-		sf2 := reflect.ValueOf(fun)
-		if sf1.Pointer() == sf2.Pointer() {
-			return
-		}
-	}
-	sessionBindCallbacks = append(sessionBindCallbacks, f)
-}
-
-// OnAfterSessionBind adds a method to be called when session is bound and after all sessionBind callbacks
-func OnAfterSessionBind(f func(ctx context.Context, s *Session) error) {
-	// Prevents the same function to be added twice in onSessionBind
-	sf1 := reflect.ValueOf(f)
-	for _, fun := range afterBindCallbacks {
-		sf2 := reflect.ValueOf(fun)
-		if sf1.Pointer() == sf2.Pointer() {
-			return
-		}
-	}
-	afterBindCallbacks = append(afterBindCallbacks, f)
-}
-
-// OnSessionClose adds a method that will be called when every session closes
-func OnSessionClose(f func(s *Session)) {
-	sf1 := reflect.ValueOf(f)
-	for _, fun := range SessionCloseCallbacks {
-		sf2 := reflect.ValueOf(fun)
-		if sf1.Pointer() == sf2.Pointer() {
-			return
-		}
-	}
-	SessionCloseCallbacks = append(SessionCloseCallbacks, f)
+// Range calls f for each frontend session until it returns false
+func Range(f func(s *Session) bool) {
+	sessionsByID.Range(func(_, value interface{}) bool {
+		return f(value.(*Session))
+	})
 }
 
 // CloseAll calls Close on all sessions
@@ -193,6 +185,7 @@ func CloseAll() {
 	logger.Debugf("closing all sessions, %d sessions", SessionCount)
 	sessionsByID.Range(func(_, value interface{}) bool {
 		s := value.(*Session)
+		s.SetCloseReason(CloseReasonShutdown)
 		s.Close()
 		return true
 	})
@@ -200,8 +193,7 @@ func CloseAll() {
 }
 
 func (s *Session) updateEncodedData() error {
-	var b []byte
-	b, err := json.Marshal(s.data)
+	b, err := dataCodec.Encode(s.data)
 	if err != nil {
 		return err
 	}
@@ -214,6 +206,18 @@ func (s *Session) Push(route string, v interface{}) error {
 	return s.entity.Push(route, v)
 }
 
+// PushContext pushes the message to the client and waits for it to be
+// sent until ctx is done, on backends batching pushes Push only buffers
+// the message
+func (s *Session) PushContext(ctx context.Context, route string, v interface{}) error {
+	if p, ok := s.entity.(interface {
+		PushContext(ctx context.Context, route string, v interface{}) error
+	}); ok {
+		return p.PushContext(ctx, route, v)
+	}
+	return s.entity.Push(route, v)
+}
+
 // ResponseMID responses message to client, mid is
 // request message ID
 func (s *Session) ResponseMID(ctx context.Context, mid uint, v interface{}, err ...bool) error {
@@ -227,7 +231,10 @@ func (s *Session) ID() int64 {
 
 // UID returns uid that bind to current session
 func (s *Session) UID() string {
-	return s.uid
+	if v, ok := s.uid.Load().(string); ok {
+		return v
+	}
+	return ""
 }
 
 // GetData gets the data
@@ -240,13 +247,35 @@ func (s *Session) GetData() map[string]interface{} {
 
 // SetData sets the whole session data
 func (s *Session) SetData(data map[string]interface{}) error {
+	defer s.dataChanged() // runs once s is unlocked
 	s.Lock()
 	defer s.Unlock()
 
+	keys := make([]string, 0, len(s.data)+len(data))
+	for k := range s.data {
+		keys = append(keys, k)
+	}
+	for k := range data {
+		keys = append(keys, k)
+	}
 	s.data = data
+	s.changed(keys...)
 	return s.updateEncodedData()
 }
 
+// CopyData returns a copy of the session data, safe to read while the
+// session changes
+func (s *Session) CopyData() map[string]interface{} {
+	s.RLock()
+	defer s.RUnlock()
+
+	data := make(map[string]interface{}, len(s.data))
+	for k, v := range s.data {
+		data[k] = v
+	}
+	return data
+}
+
 // GetDataEncoded returns the session data as an encoded value
 func (s *Session) GetDataEncoded() []byte {
 	return s.encodedData
@@ -257,12 +286,20 @@ func (s *Session) SetDataEncoded(encodedData []byte) error {
 	if len(encodedData) == 0 {
 		return nil
 	}
-	var data map[string]interface{}
-	err := json.Unmarshal(encodedData, &data)
+	data, err := dataCodec.Decode(encodedData)
 	if err != nil {
 		return err
 	}
-	return s.SetData(data)
+	if err := s.SetData(data); err != nil {
+		return err
+	}
+	if !s.IsFrontend {
+		// the data comes from the frontend, there is nothing to push back
+		s.Lock()
+		s.dirty = make(map[string]struct{})
+		s.Unlock()
+	}
+	return nil
 }
 
 // SetFrontendData sets frontend id and session id
@@ -271,6 +308,16 @@ func (s *Session) SetFrontendData(frontendID string, frontendSessionID int64) {
 	s.frontendSessionID = frontendSessionID
 }
 
+// FrontendID returns the id of the frontend that owns the session
+func (s *Session) FrontendID() string {
+	return s.frontendID
+}
+
+// FrontendSessionID returns the id of the session on its frontend
+func (s *Session) FrontendSessionID() int64 {
+	return s.frontendSessionID
+}
+
 // Bind bind UID to current session
 func (s *Session) Bind(ctx context.Context, uid string) error {
 	if uid == "" {
@@ -281,21 +328,14 @@ func (s *Session) Bind(ctx context.Context, uid string) error {
 		return constants.ErrSessionAlreadyBound
 	}
 
-	s.uid = uid
-	for _, cb := range sessionBindCallbacks {
-		err := cb(ctx, s)
-		if err != nil {
-			s.uid = ""
-			return err
-		}
+	s.uid.Store(uid)
+	if err := bindHooks.runBind(ctx, s); err != nil {
+		s.uid.Store("")
+		return err
 	}
-
-	for _, cb := range afterBindCallbacks {
-		err := cb(ctx, s)
-		if err != nil {
-			s.uid = ""
-			return err
-		}
+	if err := afterBindHooks.runBind(ctx, s); err != nil {
+		s.uid.Store("")
+		return err
 	}
 
 	// if code running on frontend tcp
@@ -307,7 +347,7 @@ func (s *Session) Bind(ctx context.Context, uid string) error {
 		err := s.bindInFront(ctx)
 		if err != nil {
 			logger.Error("error while trying to push session to front: ", err)
-			s.uid = ""
+			s.uid.Store("")
 			return err
 		}
 	}
@@ -316,6 +356,7 @@ func (s *Session) Bind(ctx context.Context, uid string) error {
 
 // Kick kicks the user
 func (s *Session) Kick(ctx context.Context) error {
+	s.SetCloseReason(CloseReasonKick)
 	err := s.entity.Kick(ctx)
 	if err != nil {
 		return err
@@ -329,6 +370,8 @@ func (s *Session) OnClose(c func()) error {
 	if !s.IsFrontend {
 		return constants.ErrOnCloseBackend
 	}
+	s.Lock()
+	defer s.Unlock()
 	s.OnCloseCallbacks = append(s.OnCloseCallbacks, c)
 	return nil
 }
@@ -336,9 +379,17 @@ func (s *Session) OnClose(c func()) error {
 // Close terminates current session, session related data will not be released,
 // all related data should be cleared explicitly in Session closed callback
 func (s *Session) Close() {
-	atomic.AddInt64(&SessionCount, -1)
+	// CloseAll and the entity both close the session when the server stops
+	s.countOnce.Do(func() {
+		if s.IsFrontend {
+			atomic.AddInt64(&SessionCount, -1)
+		}
+	})
 	sessionsByID.Delete(s.ID())
-	sessionsByUID.Delete(s.UID())
+	// the uid may be bound to a newer session already
+	if v, ok := sessionsByUID.Load(s.UID()); ok && v.(*Session) == s {
+		sessionsByUID.Delete(s.UID())
+	}
 	// TODO: this logic should be moved to nats rpc tcp
 	if s.IsFrontend && s.Subscriptions != nil && len(s.Subscriptions) > 0 {
 		// if the user is bound to an userid and nats rpc tcp is being used we need to unsubscribe
@@ -352,37 +403,87 @@ func (s *Session) Close() {
 		}
 	}
 	s.entity.Close()
+	s.RunCloseCallbacks()
+}
+
+// Touch refreshes the last time the client was heard of
+func (s *Session) Touch() {
+	atomic.StoreInt64(&s.lastTime, time.Now().Unix())
+}
+
+// CreatedAt returns when the session was created, in unix seconds
+func (s *Session) CreatedAt() int64 {
+	return s.createdAt
+}
+
+// LastTime returns the last time the client was heard of, in unix seconds
+func (s *Session) LastTime() int64 {
+	return atomic.LoadInt64(&s.lastTime)
+}
+
+// SetCloseReason records why the session is being closed, only the first
+// reason set is kept
+func (s *Session) SetCloseReason(reason string) {
+	if s.CloseReason() == "" {
+		s.closeReason.Store(reason)
+	}
+}
+
+// CloseReason returns why the session was closed, close callbacks can use it
+// to tell a kick or a heartbeat timeout from a regular disconnection
+func (s *Session) CloseReason() string {
+	if v, ok := s.closeReason.Load().(string); ok {
+		return v
+	}
+	return ""
 }
 
 // RemoteAddr returns the remote network address.
 func (s *Session) RemoteAddr() net.Addr {
 	return s.entity.RemoteAddr()
 }
+
+// GetRemoteAddr returns the ip of the client, empty when it is not known
 func (s *Session) GetRemoteAddr() string {
-	a := s.RemoteAddr().String()
-	addr := strings.Split(a, ":")
-	if len(addr) > 0 {
-		return addr[0]
+	addr := s.RemoteAddr()
+	if addr == nil {
+		return ""
 	}
-	return ""
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return addr.String()
+	}
+	return host
+}
 
+// Transport returns the transport the client is connected with, TransportTCP
+// or TransportWS, empty when it is not known
+func (s *Session) Transport() string {
+	if t, ok := s.entity.(interface{ Transport() string }); ok {
+		return t.Transport()
+	}
+	return ""
 }
 
 // Remove delete data associated with the key from session storage
 func (s *Session) Remove(key string) error {
+	defer s.dataChanged() // runs once s is unlocked
 	s.Lock()
 	defer s.Unlock()
 
 	delete(s.data, key)
+	s.changed(key)
 	return s.updateEncodedData()
 }
 
 // Set associates value with the key in session storage
 func (s *Session) Set(key string, value interface{}) error {
+	defer s.dataChanged() // runs once s is unlocked
 	s.Lock()
 	defer s.Unlock()
 
 	s.data[key] = value
+	s.changed(key)
 	return s.updateEncodedData()
 }
 
@@ -407,7 +508,8 @@ func (s *Session) Get(key string) interface{} {
 	return v
 }
 
-// Int returns the value associated with the key as a int.
+// Int returns the value associated with the key as a int, converting
+// from the other numeric types.
 func (s *Session) Int(key string) int {
 	s.RLock()
 	defer s.RUnlock()
@@ -416,15 +518,11 @@ func (s *Session) Int(key string) int {
 	if !ok {
 		return 0
 	}
-
-	value, ok := v.(int)
-	if !ok {
-		return 0
-	}
-	return value
+	return int(toInt64(v))
 }
 
-// Int8 returns the value associated with the key as a int8.
+// Int8 returns the value associated with the key as a int8, converting
+// from the other numeric types.
 func (s *Session) Int8(key string) int8 {
 	s.RLock()
 	defer s.RUnlock()
@@ -433,15 +531,11 @@ func (s *Session) Int8(key string) int8 {
 	if !ok {
 		return 0
 	}
-
-	value, ok := v.(int8)
-	if !ok {
-		return 0
-	}
-	return value
+	return int8(toInt64(v))
 }
 
-// Int16 returns the value associated with the key as a int16.
+// Int16 returns the value associated with the key as a int16, converting
+// from the other numeric types.
 func (s *Session) Int16(key string) int16 {
 	s.RLock()
 	defer s.RUnlock()
@@ -450,15 +544,11 @@ func (s *Session) Int16(key string) int16 {
 	if !ok {
 		return 0
 	}
-
-	value, ok := v.(int16)
-	if !ok {
-		return 0
-	}
-	return value
+	return int16(toInt64(v))
 }
 
-// Int32 returns the value associated with the key as a int32.
+// Int32 returns the value associated with the key as a int32, converting
+// from the other numeric types.
 func (s *Session) Int32(key string) int32 {
 	s.RLock()
 	defer s.RUnlock()
@@ -467,15 +557,11 @@ func (s *Session) Int32(key string) int32 {
 	if !ok {
 		return 0
 	}
-
-	value, ok := v.(int32)
-	if !ok {
-		return 0
-	}
-	return value
+	return int32(toInt64(v))
 }
 
-// Int64 returns the value associated with the key as a int64.
+// Int64 returns the value associated with the key as a int64, converting
+// from the other numeric types.
 func (s *Session) Int64(key string) int64 {
 	s.RLock()
 	defer s.RUnlock()
@@ -484,15 +570,11 @@ func (s *Session) Int64(key string) int64 {
 	if !ok {
 		return 0
 	}
-
-	value, ok := v.(int64)
-	if !ok {
-		return 0
-	}
-	return value
+	return int64(toInt64(v))
 }
 
-// Uint returns the value associated with the key as a uint.
+// Uint returns the value associated with the key as a uint, converting
+// from the other numeric types.
 func (s *Session) Uint(key string) uint {
 	s.RLock()
 	defer s.RUnlock()
@@ -501,15 +583,11 @@ func (s *Session) Uint(key string) uint {
 	if !ok {
 		return 0
 	}
-
-	value, ok := v.(uint)
-	if !ok {
-		return 0
-	}
-	return value
+	return uint(toUint64(v))
 }
 
-// Uint8 returns the value associated with the key as a uint8.
+// Uint8 returns the value associated with the key as a uint8, converting
+// from the other numeric types.
 func (s *Session) Uint8(key string) uint8 {
 	s.RLock()
 	defer s.RUnlock()
@@ -518,15 +596,11 @@ func (s *Session) Uint8(key string) uint8 {
 	if !ok {
 		return 0
 	}
-
-	value, ok := v.(uint8)
-	if !ok {
-		return 0
-	}
-	return value
+	return uint8(toUint64(v))
 }
 
-// Uint16 returns the value associated with the key as a uint16.
+// Uint16 returns the value associated with the key as a uint16, converting
+// from the other numeric types.
 func (s *Session) Uint16(key string) uint16 {
 	s.RLock()
 	defer s.RUnlock()
@@ -535,15 +609,11 @@ func (s *Session) Uint16(key string) uint16 {
 	if !ok {
 		return 0
 	}
-
-	value, ok := v.(uint16)
-	if !ok {
-		return 0
-	}
-	return value
+	return uint16(toUint64(v))
 }
 
-// Uint32 returns the value associated with the key as a uint32.
+// Uint32 returns the value associated with the key as a uint32, converting
+// from the other numeric types.
 func (s *Session) Uint32(key string) uint32 {
 	s.RLock()
 	defer s.RUnlock()
@@ -552,15 +622,11 @@ func (s *Session) Uint32(key string) uint32 {
 	if !ok {
 		return 0
 	}
-
-	value, ok := v.(uint32)
-	if !ok {
-		return 0
-	}
-	return value
+	return uint32(toUint64(v))
 }
 
-// Uint64 returns the value associated with the key as a uint64.
+// Uint64 returns the value associated with the key as a uint64, converting
+// from the other numeric types.
 func (s *Session) Uint64(key string) uint64 {
 	s.RLock()
 	defer s.RUnlock()
@@ -569,15 +635,11 @@ func (s *Session) Uint64(key string) uint64 {
 	if !ok {
 		return 0
 	}
-
-	value, ok := v.(uint64)
-	if !ok {
-		return 0
-	}
-	return value
+	return uint64(toUint64(v))
 }
 
-// Float32 returns the value associated with the key as a float32.
+// Float32 returns the value associated with the key as a float32, converting
+// from the other numeric types.
 func (s *Session) Float32(key string) float32 {
 	s.RLock()
 	defer s.RUnlock()
@@ -586,15 +648,11 @@ func (s *Session) Float32(key string) float32 {
 	if !ok {
 		return 0
 	}
-
-	value, ok := v.(float32)
-	if !ok {
-		return 0
-	}
-	return value
+	return float32(toFloat64(v))
 }
 
-// Float64 returns the value associated with the key as a float64.
+// Float64 returns the value associated with the key as a float64, converting
+// from the other numeric types.
 func (s *Session) Float64(key string) float64 {
 	s.RLock()
 	defer s.RUnlock()
@@ -603,12 +661,7 @@ func (s *Session) Float64(key string) float64 {
 	if !ok {
 		return 0
 	}
-
-	value, ok := v.(float64)
-	if !ok {
-		return 0
-	}
-	return value
+	return float64(toFloat64(v))
 }
 
 // String returns the value associated with the key as a string.
@@ -639,33 +692,51 @@ func (s *Session) Value(key string) interface{} {
 func (s *Session) bindInFront(ctx context.Context) error {
 	sessionData := &proto_mcbeam.Session{
 		Id:  s.frontendSessionID,
-		Uid: s.uid,
+		Uid: s.UID(),
 	}
 	err := s.entity.SendRequest(ctx, constants.BindRoute, sessionData, &proto_mcbeam.Response{})
 	return err
 }
 
-// PushToFront updates the session in the frontend
+// PushToFront sends the keys set or removed since the last push to the
+// frontend, it fails with a conflict when the frontend changed them since
+// the session was read
 func (s *Session) PushToFront(ctx context.Context) error {
 	if s.IsFrontend {
 		return constants.ErrFrontSessionCantPushToFront
 	}
-	sessionData := &proto_mcbeam.Session{
-		Id:   s.frontendSessionID,
-		Uid:  s.uid,
-		Data: s.encodedData,
+	delta, keys, err := s.takeDelta()
+	if err != nil || delta == nil {
+		return err
 	}
-	err := s.entity.SendRequest(ctx, constants.PushSessionRoute, sessionData, &proto_mcbeam.Response{})
-	return err
+	rsp := &proto_mcbeam.SessionDeltaAnswer{}
+	err = s.entity.SendRequest(ctx, constants.PushSessionDeltaRoute, delta, rsp)
+	s.Lock()
+	defer s.Unlock()
+	if err != nil {
+		// keep the keys to push them again
+		for _, k := range keys {
+			s.dirty[k] = struct{}{}
+		}
+		return err
+	}
+	s.version = rsp.GetVersion()
+	return nil
 }
 
 // Clear releases all data related to current session
 func (s *Session) Clear() {
+	defer s.dataChanged() // runs once s is unlocked
 	s.Lock()
 	defer s.Unlock()
 
-	s.uid = ""
+	s.uid.Store("")
+	keys := make([]string, 0, len(s.data))
+	for k := range s.data {
+		keys = append(keys, k)
+	}
 	s.data = map[string]interface{}{}
+	s.changed(keys...)
 	s.updateEncodedData()
 }
 
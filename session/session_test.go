@@ -0,0 +1,36 @@
+package session
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// closeEntity is a network entity that only counts its closes
+type closeEntity struct {
+	NetworkEntity
+	closed int
+}
+
+func (e *closeEntity) Close() error {
+	e.closed++
+	return nil
+}
+
+func TestCloseDecrementsCountOnce(t *testing.T) {
+	before := atomic.LoadInt64(&SessionCount)
+	entity := &closeEntity{}
+	s := New(entity, true)
+	assert.Equal(t, before+1, atomic.LoadInt64(&SessionCount))
+
+	// CloseAll and the entity read loop both close it
+	s.Close()
+	s.Close()
+	assert.Equal(t, before, atomic.LoadInt64(&SessionCount))
+	assert.Equal(t, 2, entity.closed)
+
+	backend := New(&closeEntity{}, false)
+	backend.Close()
+	assert.Equal(t, before, atomic.LoadInt64(&SessionCount))
+}
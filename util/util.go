@@ -35,6 +35,7 @@ import (
 	"github.com/wolfplus2048/mcbeam-plus/route"
 	"github.com/wolfplus2048/mcbeam-plus/serialize"
 	"github.com/wolfplus2048/mcbeam-plus/session"
+	"net"
 	"os"
 	"reflect"
 	"runtime/debug"
@@ -166,10 +167,12 @@ func BuildRequest(ctx context.Context,
 			mid = msg.ID
 		}
 		req.Msg.Id = uint64(mid)
+		// backends get the data from their cache, or from the frontend
+		// when their copy is older than version
 		req.Session = &proto_mcbeam.Session{
-			Id:   session.ID(),
-			Uid:  session.UID(),
-			Data: session.GetDataEncoded(),
+			Id:      session.ID(),
+			Uid:     session.UID(),
+			Version: session.Version(),
 		}
 	}
 	return req, nil
@@ -192,7 +195,19 @@ func BuildMcbContext(ctx context.Context,
 	md["mcb-session-id"] = strconv.FormatInt(session.ID(), 10)
 	md["mcb-session-uid"] = session.UID()
 	md["mcb-session-fid"] = frontendID
-	md["mcb-session-data"] = string(session.GetDataEncoded())
+	md["mcb-session-version"] = strconv.FormatUint(session.Version(), 10)
+	// the client connection, backends have no other way to know it
+	if addr := session.RemoteAddr(); addr != nil {
+		if host, port, err := net.SplitHostPort(addr.String()); err == nil {
+			md["mcb-client-ip"] = host
+			md["mcb-client-port"] = port
+		}
+	}
+	md["mcb-client-transport"] = session.Transport()
+	if hd := session.GetHandshakeData(); hd != nil {
+		md["mcb-client-platform"] = hd.Sys.Platform
+		md["mcb-client-version"] = hd.Sys.Version
+	}
 	return metadata.NewContext(ctx, md)
 }
 
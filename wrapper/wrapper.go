@@ -27,11 +27,17 @@ func SessionHandler(client client.Client) server.HandlerWrapper {
 			fid, ok := metadata.Get(ctx, "mcb-session-fid")
 
 			reply, ok := metadata.Get(ctx, "mcb-session-reply")
+			version, _ := metadata.Get(ctx, "mcb-session-version")
+			v, _ := strconv.ParseUint(version, 10, 64)
 			session := &proto_mcbeam.Session{
-				Id:  aid,
-				Uid: uid,
+				Id:      aid,
+				Uid:     uid,
+				Version: v,
+			}
+			a, err := agent.NewRemote(ctx, session, reply, client, fid, protobuf.NewSerializer())
+			if err != nil {
+				return err
 			}
-			a, _ := agent.NewRemote(session, reply, client, fid, protobuf.NewSerializer())
 
 			ctx = context.WithValue(ctx, constants.SessionCtxKey, a.Session)
 			return h(ctx, req, rsp)